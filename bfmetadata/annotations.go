@@ -0,0 +1,375 @@
+package bfmetadata
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type StructuredAnnotations struct {
+	XMLAnnotations       []XMLAnnotation       `xml:"XMLAnnotation"`
+	BooleanAnnotations   []BooleanAnnotation   `xml:"BooleanAnnotation"`
+	TimestampAnnotations []TimestampAnnotation `xml:"TimestampAnnotation"`
+	LongAnnotations      []LongAnnotation      `xml:"LongAnnotation"`
+	DoubleAnnotations    []DoubleAnnotation    `xml:"DoubleAnnotation"`
+	CommentAnnotations   []CommentAnnotation   `xml:"CommentAnnotation"`
+	TagAnnotations       []TagAnnotation       `xml:"TagAnnotation"`
+	MapAnnotations       []MapAnnotation       `xml:"MapAnnotation"`
+}
+
+// AnnotationBase holds the attributes shared by all structured annotations.
+type AnnotationBase struct {
+	ID             string          `xml:"ID,attr,omitempty"`
+	Namespace      string          `xml:"Namespace,attr,omitempty"`
+	Annotator      string          `xml:"Annotator,attr,omitempty"`
+	Description    string          `xml:"Description,omitempty"`
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type MapAnnotation struct {
+	AnnotationBase
+	Values []KeyValuePair `xml:"Value>M"`
+}
+
+type KeyValuePair struct {
+	Key   string `xml:"K,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// XMLAnnotation holds an arbitrary XML blob. Value is the raw inner XML of
+// the annotation's Value element.
+type XMLAnnotation struct {
+	AnnotationBase
+	Value string `xml:"-"`
+}
+
+type BooleanAnnotation struct {
+	AnnotationBase
+	Value string `xml:"Value,omitempty"`
+}
+
+type TimestampAnnotation struct {
+	AnnotationBase
+	Value string `xml:"Value,omitempty"`
+}
+
+type LongAnnotation struct {
+	AnnotationBase
+	Value string `xml:"Value,omitempty"`
+}
+
+type DoubleAnnotation struct {
+	AnnotationBase
+	Value string `xml:"Value,omitempty"`
+}
+
+type CommentAnnotation struct {
+	AnnotationBase
+	Value string `xml:"Value,omitempty"`
+}
+
+type TagAnnotation struct {
+	AnnotationBase
+	Value string `xml:"Value,omitempty"`
+}
+
+// xmlAnnotationValue captures the Value element of an XMLAnnotation verbatim.
+type xmlAnnotationValue struct {
+	InnerXML string `xml:",innerxml"`
+}
+
+// UnmarshalXML keeps the content of the Value element as raw XML.
+func (a *XMLAnnotation) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw struct {
+		AnnotationBase
+		Value xmlAnnotationValue `xml:"Value"`
+	}
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	a.AnnotationBase = raw.AnnotationBase
+	a.Value = raw.Value.InnerXML
+	return nil
+}
+
+// MarshalXML writes Value back verbatim as the content of the Value element.
+func (a XMLAnnotation) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	raw := struct {
+		AnnotationBase
+		Value xmlAnnotationValue `xml:"Value"`
+	}{a.AnnotationBase, xmlAnnotationValue{a.Value}}
+
+	return e.EncodeElement(raw, start)
+}
+
+// Annotation is a type-independent view of one structured annotation. Type
+// is the OME element name, e.g. "MapAnnotation". Values is only set for map
+// annotations; Value holds the content of all other annotation types.
+type Annotation struct {
+	AnnotationBase
+	Type   string
+	Value  string
+	Values []KeyValuePair
+}
+
+// annotatedElement identifies an element carrying an AnnotationRef.
+type annotatedElement struct {
+	Type string
+	ID   string
+}
+
+// All returns every structured annotation as a generic Annotation, grouped
+// by annotation type.
+func (sa StructuredAnnotations) All() []Annotation {
+	var all []Annotation
+	add := func(typ string, base AnnotationBase, value string) {
+		all = append(all, Annotation{AnnotationBase: base, Type: typ, Value: value})
+	}
+
+	for _, a := range sa.XMLAnnotations {
+		add("XMLAnnotation", a.AnnotationBase, a.Value)
+	}
+	for _, a := range sa.BooleanAnnotations {
+		add("BooleanAnnotation", a.AnnotationBase, a.Value)
+	}
+	for _, a := range sa.TimestampAnnotations {
+		add("TimestampAnnotation", a.AnnotationBase, a.Value)
+	}
+	for _, a := range sa.LongAnnotations {
+		add("LongAnnotation", a.AnnotationBase, a.Value)
+	}
+	for _, a := range sa.DoubleAnnotations {
+		add("DoubleAnnotation", a.AnnotationBase, a.Value)
+	}
+	for _, a := range sa.CommentAnnotations {
+		add("CommentAnnotation", a.AnnotationBase, a.Value)
+	}
+	for _, a := range sa.TagAnnotations {
+		add("TagAnnotation", a.AnnotationBase, a.Value)
+	}
+	for _, a := range sa.MapAnnotations {
+		all = append(all, Annotation{AnnotationBase: a.AnnotationBase, Type: "MapAnnotation", Values: a.Values})
+	}
+
+	return all
+}
+
+// annotationTargets maps annotation IDs to the elements that reference them.
+func annotationTargets(ome *OME) map[string][]annotatedElement {
+	targets := make(map[string][]annotatedElement)
+	link := func(refs []AnnotationRef, typ, id string) {
+		for _, ref := range refs {
+			targets[ref.ID] = append(targets[ref.ID], annotatedElement{Type: typ, ID: id})
+		}
+	}
+
+	for _, experimenter := range ome.Experimenters {
+		link(experimenter.AnnotationRefs, "Experimenter", experimenter.ID)
+	}
+	for _, group := range ome.ExperimenterGroups {
+		link(group.AnnotationRefs, "ExperimenterGroup", group.ID)
+	}
+	for _, screen := range ome.Screens {
+		link(screen.AnnotationRefs, "Screen", screen.ID)
+	}
+	for _, plate := range ome.Plates {
+		link(plate.AnnotationRefs, "Plate", plate.ID)
+		for _, well := range plate.Wells {
+			link(well.AnnotationRefs, "Well", well.ID)
+		}
+		for _, acquisition := range plate.PlateAcquisitions {
+			link(acquisition.AnnotationRefs, "PlateAcquisition", acquisition.ID)
+		}
+	}
+	for _, image := range ome.Images {
+		link(image.AnnotationRefs, "Image", image.ID)
+		link(image.Pixels.AnnotationRefs, "Pixels", image.Pixels.ID)
+		for _, channel := range image.Pixels.Channels {
+			link(channel.AnnotationRefs, "Channel", channel.ID)
+		}
+		for i, plane := range image.Pixels.Planes {
+			link(plane.AnnotationRefs, "Plane", fmt.Sprintf("%s/Plane[%d]", image.Pixels.ID, i))
+		}
+	}
+	for _, roi := range ome.ROIs {
+		link(roi.AnnotationRefs, "ROI", roi.ID)
+	}
+	for _, annotation := range ome.StructuredAnnotations.All() {
+		link(annotation.AnnotationRefs, annotation.Type, annotation.ID)
+	}
+
+	return targets
+}
+
+// ExportAnnotationsAsCSV writes all structured annotations as CSV. Map
+// annotations produce one row per key-value pair and every row is repeated
+// for each element referencing the annotation.
+func ExportAnnotationsAsCSV(ome *OME, w io.Writer) error {
+	if ome == nil {
+		return fmt.Errorf("no OME metadata provided")
+	}
+
+	cw := csv.NewWriter(w)
+	header := []string{"AnnotationID", "AnnotationType", "Namespace", "Key", "Value", "AnnotatedElementType", "AnnotatedElementID"}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	targets := annotationTargets(ome)
+	for _, annotation := range ome.StructuredAnnotations.All() {
+		pairs := annotation.Values
+		if annotation.Type != "MapAnnotation" {
+			pairs = []KeyValuePair{{Value: annotation.Value}}
+		}
+
+		elements := targets[annotation.ID]
+		if len(elements) == 0 {
+			elements = []annotatedElement{{}}
+		}
+
+		for _, pair := range pairs {
+			for _, element := range elements {
+				record := []string{annotation.ID, annotation.Type, annotation.Namespace, pair.Key, pair.Value, element.Type, element.ID}
+				if err := cw.Write(record); err != nil {
+					return fmt.Errorf("error writing CSV record: %w", err)
+				}
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// linkedMapAnnotations returns the map annotations referenced by refs, in
+// reference order.
+func linkedMapAnnotations(ome *OME, refs []AnnotationRef) []MapAnnotation {
+	byID := make(map[string]MapAnnotation)
+	for _, a := range ome.StructuredAnnotations.MapAnnotations {
+		byID[a.ID] = a
+	}
+
+	var linked []MapAnnotation
+	for _, ref := range refs {
+		if a, ok := byID[ref.ID]; ok {
+			linked = append(linked, a)
+		}
+	}
+
+	return linked
+}
+
+// GetAnnotationRefCount returns the number of AnnotationRef elements that
+// reference annotationID.
+func GetAnnotationRefCount(ome *OME, annotationID string) int {
+	if ome == nil {
+		return 0
+	}
+
+	return len(annotationTargets(ome)[annotationID])
+}
+
+// GetOrphanedAnnotations returns the annotations that no element references.
+func GetOrphanedAnnotations(ome *OME) []Annotation {
+	if ome == nil {
+		return nil
+	}
+
+	targets := annotationTargets(ome)
+
+	var orphaned []Annotation
+	for _, annotation := range ome.StructuredAnnotations.All() {
+		if len(targets[annotation.ID]) == 0 {
+			orphaned = append(orphaned, annotation)
+		}
+	}
+
+	return orphaned
+}
+
+// GetAnnotationsAsKeyValueMap merges the key-value pairs of all map
+// annotations linked to the image with the given ID. Annotations are applied
+// in AnnotationRef order, so a later annotation overrides an earlier value
+// for the same key. A key defined by annotations with different namespaces
+// is reported as a collision error.
+func GetAnnotationsAsKeyValueMap(ome *OME, imageID string) (map[string]string, error) {
+	image, err := getImageByID(ome, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	namespaces := make(map[string]string)
+	for _, annotation := range linkedMapAnnotations(ome, image.AnnotationRefs) {
+		for _, pair := range annotation.Values {
+			if ns, seen := namespaces[pair.Key]; seen && ns != annotation.Namespace {
+				return nil, fmt.Errorf("key %q defined in namespaces %q and %q", pair.Key, ns, annotation.Namespace)
+			}
+			namespaces[pair.Key] = annotation.Namespace
+			values[pair.Key] = pair.Value
+		}
+	}
+
+	return values, nil
+}
+
+// GetAnnotations is a wrapper around the default Client's GetAnnotations.
+func GetAnnotations(ctx context.Context, filePath string) ([]MapAnnotation, []XMLAnnotation, error) {
+	return defaultClient.GetAnnotations(ctx, filePath)
+}
+
+// GetAnnotations extracts the OME-XML of filePath and returns its map and
+// XML annotations, where vendors commonly store proprietary acquisition
+// parameters.
+func (c *Client) GetAnnotations(ctx context.Context, filePath string) ([]MapAnnotation, []XMLAnnotation, error) {
+	metadata, err := c.getParsedMetadata(ctx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return metadata.StructuredAnnotations.MapAnnotations, metadata.StructuredAnnotations.XMLAnnotations, nil
+}
+
+// AnnotatedImage is an image together with the annotations and ROIs it
+// references.
+type AnnotatedImage struct {
+	Image
+	Annotations []Annotation
+	ROIs        []ROI
+}
+
+// GetAnnotatedImage returns the image of the given series with its linked
+// structured annotations and ROIs resolved, in reference order. References
+// to missing elements are skipped.
+func GetAnnotatedImage(ome *OME, seriesIdx int) (AnnotatedImage, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return AnnotatedImage{}, err
+	}
+
+	annotations := make(map[string]Annotation)
+	for _, a := range ome.StructuredAnnotations.All() {
+		annotations[a.ID] = a
+	}
+	rois := make(map[string]ROI)
+	for _, roi := range ome.ROIs {
+		rois[roi.ID] = roi
+	}
+
+	annotated := AnnotatedImage{Image: *image}
+	for _, ref := range image.AnnotationRefs {
+		if a, ok := annotations[ref.ID]; ok {
+			annotated.Annotations = append(annotated.Annotations, a)
+		}
+	}
+	for _, ref := range image.ROIRefs {
+		if roi, ok := rois[ref.ID]; ok {
+			annotated.ROIs = append(annotated.ROIs, roi)
+		}
+	}
+
+	return annotated, nil
+}
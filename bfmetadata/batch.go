@@ -0,0 +1,56 @@
+package bfmetadata
+
+import (
+	"context"
+	"sync"
+)
+
+// Result is the outcome of extracting the essential metadata of one file in
+// a batch.
+type Result struct {
+	Path     string
+	Metadata *EssentialMetadata
+	Err      error
+}
+
+// GetEssentialMetadataForFiles is a wrapper around the default Client's GetEssentialMetadataForFiles.
+func GetEssentialMetadataForFiles(ctx context.Context, paths []string, workers int) ([]Result, error) {
+	return defaultClient.GetEssentialMetadataForFiles(ctx, paths, workers)
+}
+
+// GetEssentialMetadataForFiles extracts the essential metadata of every path
+// running at most workers showinf processes at a time. Results are returned
+// in the order of paths and carry per-file errors, so one unreadable file
+// does not fail the batch. When ctx is done, running tools are killed, files
+// not yet started report ctx.Err() and ctx.Err() is returned.
+func (c *Client) GetEssentialMetadataForFiles(ctx context.Context, paths []string, workers int) ([]Result, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]Result, len(paths))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, path := range paths {
+		results[i].Path = path
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(r *Result) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r.Metadata, r.Err = c.GetEssentialMetadata(ctx, r.Path)
+		}(&results[i])
+	}
+
+	wg.Wait()
+	return results, ctx.Err()
+}
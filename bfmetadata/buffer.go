@@ -0,0 +1,48 @@
+package bfmetadata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GetOMEXMLFromBuffer is a wrapper around the default Client's GetOMEXMLFromBuffer.
+func GetOMEXMLFromBuffer(ctx context.Context, fileBytes []byte, extension string) (string, error) {
+	return defaultClient.GetOMEXMLFromBuffer(ctx, fileBytes, extension)
+}
+
+// GetOMEXMLFromBuffer extracts OME-XML from image data already held in
+// memory. The bytes are written to a temporary file in the client's tool
+// directory, named after their SHA-256 digest plus a per-call suffix and
+// with extension appended so Bio-Formats can detect the format. The file is
+// removed once showinf has finished.
+func (c *Client) GetOMEXMLFromBuffer(ctx context.Context, fileBytes []byte, extension string) (string, error) {
+	if extension != "" && !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+
+	dir, err := c.prepare()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(fileBytes)
+	f, err := os.CreateTemp(dir, "bfmetadata-"+hex.EncodeToString(sum[:])+"-*"+extension)
+	if err != nil {
+		return "", fmt.Errorf("error creating temp file for buffer: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(fileBytes)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("error writing buffer to temp file: %w", err)
+	}
+
+	return c.GetOmexmlMetadata(ctx, f.Name(), AllSeries)
+}
@@ -0,0 +1,63 @@
+package bfmetadata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetOMEXMLFromBufferConcurrent(t *testing.T) {
+	installFakeJava(t, `for arg in "$@"; do
+  case "$arg" in *.tif) file=$arg ;; esac
+done
+sleep 0.2
+if [ ! -f "$file" ]; then
+  echo "missing $file" >&2
+  exit 1
+fi
+echo "<?xml version=\"1.0\"?><OME xmlns=\"http://www.openmicroscopy.org/Schemas/OME/2016-06\"><Image ID=\"Image:0\" Name=\"$file\"/></OME>"
+`)
+
+	dir := t.TempDir()
+	c := NewClient(WithTempDir(dir))
+	data := []byte("II*\x00 not really a TIFF")
+
+	paths := make([]string, 2)
+	var wg sync.WaitGroup
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			xmlData, err := c.GetOMEXMLFromBuffer(context.Background(), data, "tif")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ome, err := parseXML(xmlData)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			paths[i] = ome.Images[0].Name
+		}(i)
+	}
+	wg.Wait()
+
+	if paths[0] == paths[1] {
+		t.Errorf("concurrent calls shared temp file %s", paths[0])
+	}
+	for _, path := range paths {
+		if filepath.Dir(path) != dir {
+			t.Errorf("temp file %s not in tool directory %s", path, dir)
+		}
+		if !strings.HasPrefix(filepath.Base(path), "bfmetadata-") || !strings.HasSuffix(path, ".tif") {
+			t.Errorf("unexpected temp file name %s", path)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("temp file %s not removed", path)
+		}
+	}
+}
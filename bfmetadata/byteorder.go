@@ -0,0 +1,39 @@
+package bfmetadata
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// GetByteOrderForSeries interprets Pixels.BigEndian for a series. A missing
+// or unrecognised value is an error.
+func GetByteOrderForSeries(ome *OME, seriesIdx int) (binary.ByteOrder, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(image.Pixels.BigEndian)) {
+	case "true":
+		return binary.BigEndian, nil
+	case "false":
+		return binary.LittleEndian, nil
+	case "":
+		return nil, fmt.Errorf("series %d has no BigEndian attribute", seriesIdx)
+	}
+
+	return nil, fmt.Errorf("series %d has invalid BigEndian value %q", seriesIdx, image.Pixels.BigEndian)
+}
+
+// GetNativeByteOrder returns binary.LittleEndian or binary.BigEndian
+// according to the byte order of the running system.
+func GetNativeByteOrder() binary.ByteOrder {
+	var buf [2]byte
+	binary.NativeEndian.PutUint16(buf[:], 1)
+	if buf[0] == 1 {
+		return binary.LittleEndian
+	}
+
+	return binary.BigEndian
+}
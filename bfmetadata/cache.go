@@ -0,0 +1,65 @@
+package bfmetadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GetOMEXMLFromCache returns OME-XML previously stored with WriteOMEXMLToCache.
+// The cached copy is only used when it is at least as new as the image file;
+// a missing or stale entry returns ("", false, nil).
+func GetOMEXMLFromCache(cacheDir, filePath string) (string, bool, error) {
+	cachePath := cacheFilePath(cacheDir, filePath)
+
+	cacheInfo, err := os.Stat(cachePath)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("error checking cache file %s: %w", cachePath, err)
+	}
+
+	sourceInfo, err := os.Stat(filePath)
+	if err != nil {
+		return "", false, fmt.Errorf("error checking image file %s: %w", filePath, err)
+	}
+	if cacheInfo.ModTime().Before(sourceInfo.ModTime()) {
+		return "", false, nil
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return "", false, fmt.Errorf("error reading cache file %s: %w", cachePath, err)
+	}
+
+	return string(data), true, nil
+}
+
+// WriteOMEXMLToCache stores xmlData in cacheDir under a name derived from
+// the SHA-256 of filePath.
+func WriteOMEXMLToCache(cacheDir, filePath, xmlData string) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	cachePath := cacheFilePath(cacheDir, filePath)
+	if err := os.WriteFile(cachePath, []byte(xmlData), 0644); err != nil {
+		return fmt.Errorf("error writing cache file %s: %w", cachePath, err)
+	}
+
+	return nil
+}
+
+// cacheFilePath returns the cache location for an image file. The absolute
+// path is hashed so that relative and absolute references share an entry.
+func cacheFilePath(cacheDir, filePath string) string {
+	if abs, err := filepath.Abs(filePath); err == nil {
+		filePath = abs
+	}
+
+	sum := sha256.Sum256([]byte(filePath))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:])+".xml")
+}
@@ -0,0 +1,228 @@
+package bfmetadata
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GetChannelSamplesPerPixel returns the SamplesPerPixel of a channel.
+// A missing value is reported as 1, the Bio-Formats default.
+func GetChannelSamplesPerPixel(ome *OME, seriesIdx, channelIdx int) (int, error) {
+	channel, err := getChannel(ome, seriesIdx, channelIdx)
+	if err != nil {
+		return 0, err
+	}
+
+	return samplesPerPixel(*channel), nil
+}
+
+// GetTotalSamplesPerPlane returns the sum of SamplesPerPixel over all
+// channels of a series, which is the number of samples stored per pixel.
+func GetTotalSamplesPerPlane(ome *OME, seriesIdx int) (int, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(image.Pixels.Channels) == 0 {
+		return max(image.Pixels.SizeC, 1), nil
+	}
+
+	total := 0
+	for _, channel := range image.Pixels.Channels {
+		total += samplesPerPixel(channel)
+	}
+
+	return total, nil
+}
+
+// GetSignificantBitsPerChannel returns the significant bits of every channel
+// of a series. Some writers record a per-channel SignificantBits attribute
+// for sensors whose channels differ in bit depth; channels without one use
+// Pixels.SignificantBits. A series without Channel elements yields SizeC
+// copies of Pixels.SignificantBits.
+func GetSignificantBitsPerChannel(ome *OME, seriesIdx int) ([]int, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	p := image.Pixels
+	if len(p.Channels) == 0 {
+		bits := make([]int, max(p.SizeC, 1))
+		for i := range bits {
+			bits[i] = p.SignificantBits
+		}
+		return bits, nil
+	}
+
+	bits := make([]int, len(p.Channels))
+	for i, channel := range p.Channels {
+		bits[i] = p.SignificantBits
+		if channel.SignificantBits > 0 {
+			bits[i] = channel.SignificantBits
+		}
+	}
+
+	return bits, nil
+}
+
+// samplesPerPixel returns the channel's SamplesPerPixel, defaulting to 1.
+func samplesPerPixel(channel Channel) int {
+	if channel.SamplesPerPixel < 1 {
+		return 1
+	}
+
+	return channel.SamplesPerPixel
+}
+
+// GetChannelByName returns the first channel of a series whose Name matches
+// name case-insensitively, together with its index.
+func GetChannelByName(ome *OME, seriesIdx int, name string) (Channel, int, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return Channel{}, -1, err
+	}
+
+	for i, channel := range image.Pixels.Channels {
+		if strings.EqualFold(channel.Name, name) {
+			return channel, i, nil
+		}
+	}
+
+	return Channel{}, -1, fmt.Errorf("no channel named %q in series %d", name, seriesIdx)
+}
+
+// GetChannelsByFluor returns all channels of a series whose Fluor matches
+// fluor case-insensitively.
+func GetChannelsByFluor(ome *OME, seriesIdx int, fluor string) ([]Channel, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	var channels []Channel
+	for _, channel := range image.Pixels.Channels {
+		if strings.EqualFold(channel.Fluor, fluor) {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels, nil
+}
+
+// ContrastMethod is the OME Channel ContrastMethod enumeration.
+type ContrastMethod string
+
+const (
+	ContrastMethodBrightfield         ContrastMethod = "Brightfield"
+	ContrastMethodPhase               ContrastMethod = "Phase"
+	ContrastMethodDIC                 ContrastMethod = "DIC"
+	ContrastMethodHoffmanModulation   ContrastMethod = "HoffmanModulation"
+	ContrastMethodObliqueIllumination ContrastMethod = "ObliqueIllumination"
+	ContrastMethodPolarizedLight      ContrastMethod = "PolarizedLight"
+	ContrastMethodDarkfield           ContrastMethod = "Darkfield"
+	ContrastMethodFluorescence        ContrastMethod = "Fluorescence"
+	ContrastMethodOther               ContrastMethod = "Other"
+)
+
+// GetChannelContrastMethod returns the ContrastMethod of a channel, which is
+// empty when the attribute is not set.
+func GetChannelContrastMethod(ome *OME, seriesIdx, channelIdx int) (ContrastMethod, error) {
+	channel, err := getChannel(ome, seriesIdx, channelIdx)
+	if err != nil {
+		return "", err
+	}
+
+	return channel.ContrastMethod, nil
+}
+
+// GetNonFluorescenceChannels returns the channels of a series that are not
+// fluorescence channels, such as transmitted-light channels. Channels without
+// a ContrastMethod are classified by their illumination type, fluorophore
+// and wavelengths.
+func GetNonFluorescenceChannels(ome *OME, seriesIdx int) ([]Channel, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	var channels []Channel
+	for _, channel := range image.Pixels.Channels {
+		if !isFluorescenceChannel(channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels, nil
+}
+
+// channelCSVHeader lists the columns written by ExportChannelMetadataToCSV.
+var channelCSVHeader = []string{
+	"SeriesIndex", "SeriesName", "ChannelIndex", "ID", "Name", "SamplesPerPixel",
+	"IlluminationType", "ContrastMethod", "ExcitationWavelength", "ExcitationWavelengthUnit",
+	"EmissionWavelength", "EmissionWavelengthUnit", "Fluor", "PinholeSize", "PinholeSizeUnit",
+	"Color", "SignificantBits", "DetectorID", "FilterSetID",
+}
+
+// ExportChannelMetadataToCSV writes one CSV row per channel of every series.
+// Unset numeric attributes are written as empty cells.
+func ExportChannelMetadataToCSV(ome *OME, w io.Writer) error {
+	if ome == nil {
+		return fmt.Errorf("no OME metadata provided")
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(channelCSVHeader); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	for seriesIdx, image := range ome.Images {
+		for channelIdx, channel := range image.Pixels.Channels {
+			detectorID := ""
+			if channel.DetectorSettings != nil {
+				detectorID = channel.DetectorSettings.ID
+			}
+			filterSetID := ""
+			if channel.FilterSetRef != nil {
+				filterSetID = channel.FilterSetRef.ID
+			}
+
+			record := []string{
+				strconv.Itoa(seriesIdx), image.Name, strconv.Itoa(channelIdx), channel.ID, channel.Name,
+				csvInt(channel.SamplesPerPixel), channel.IlluminationType, string(channel.ContrastMethod),
+				csvFloat(channel.ExcitationWavelength), channel.ExcitationWavelengthUnit,
+				csvFloat(channel.EmissionWavelength), channel.EmissionWavelengthUnit, channel.Fluor,
+				csvFloat(channel.PinholeSize), channel.PinholeSizeUnit, channel.Color,
+				csvInt(channel.SignificantBits), detectorID, filterSetID,
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("error writing CSV record: %w", err)
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvInt formats an optional integer attribute, leaving zero values empty.
+func csvInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(v)
+}
+
+// csvFloat formats an optional float attribute, leaving zero values empty.
+func csvFloat(v float64) string {
+	if v == 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
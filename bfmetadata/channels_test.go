@@ -0,0 +1,34 @@
+package bfmetadata
+
+import "testing"
+
+func TestGetNonFluorescenceChannels(t *testing.T) {
+	ome := &OME{Images: []Image{{Pixels: Pixels{Channels: []Channel{
+		{ID: "Channel:0:0", ContrastMethod: ContrastMethodFluorescence},
+		{ID: "Channel:0:1", ContrastMethod: ContrastMethodBrightfield},
+		{ID: "Channel:0:2", EmissionWavelength: 520},
+		{ID: "Channel:0:3", Fluor: "DAPI"},
+		{ID: "Channel:0:4", IlluminationType: "Transmitted"},
+		{ID: "Channel:0:5"},
+	}}}}}
+
+	channels, err := GetNonFluorescenceChannels(ome, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, channel := range channels {
+		ids = append(ids, channel.ID)
+	}
+	want := []string{"Channel:0:1", "Channel:0:4", "Channel:0:5"}
+	if len(ids) != len(want) {
+		t.Fatalf("got %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("got %v, want %v", ids, want)
+			break
+		}
+	}
+}
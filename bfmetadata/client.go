@@ -0,0 +1,106 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Client runs the embedded Bio-Formats tools with its own configuration.
+// The package-level functions that invoke the tools use a default Client;
+// functions working on already parsed metadata need no client.
+type Client struct {
+	tempDir string
+	jvmHeap string
+	logger  Logger
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithTempDir sets the directory the embedded tools are extracted to. The
+// default is $BF_TOOL_DIR, falling back to a "bioformats" directory under
+// os.TempDir.
+func WithTempDir(dir string) Option {
+	return func(c *Client) {
+		c.tempDir = dir
+	}
+}
+
+// DefaultJVMMaxMemory is the maximum Java heap size passed to the tools when
+// neither WithJVMMaxMemory nor the BF_MAX_MEM environment variable set one.
+const DefaultJVMMaxMemory = "2g"
+
+// WithJVMMaxMemory sets the maximum Java heap size, e.g. "8g", passed to
+// the tools as BF_MAX_MEM.
+func WithJVMMaxMemory(size string) Option {
+	return func(c *Client) {
+		c.jvmHeap = size
+	}
+}
+
+// WithJVMHeap is an alias for WithJVMMaxMemory.
+func WithJVMHeap(size string) Option {
+	return WithJVMMaxMemory(size)
+}
+
+// WithLogger sets the logger receiving the client's diagnostic messages
+// instead of the package-level logger.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// NewClient returns a Client configured by opts.
+func NewClient(opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// defaultClient backs the package-level functions.
+var defaultClient = NewClient()
+
+// defaultToolDir returns the directory the embedded tools are extracted to
+// when no temp directory is configured: $BF_TOOL_DIR if set, otherwise a
+// "bioformats" directory under os.TempDir.
+func defaultToolDir() string {
+	if dir := os.Getenv("BF_TOOL_DIR"); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(os.TempDir(), "bioformats")
+}
+
+// hasLogger reports whether the client's messages reach a logger.
+func (c *Client) hasLogger() bool {
+	return c.logger != nil || hasPackageLogger()
+}
+
+// logf forwards a message to the client's logger, falling back to the
+// package-level logger.
+func (c *Client) logf(level, format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Log(level, fmt.Sprintf(format, args...))
+		return
+	}
+
+	logf(level, format, args...)
+}
+
+// jvmMaxMemory returns the BF_MAX_MEM value for the tools, or "" to keep the
+// value already set in the environment.
+func (c *Client) jvmMaxMemory() string {
+	if c.jvmHeap != "" {
+		return c.jvmHeap
+	}
+	if os.Getenv("BF_MAX_MEM") != "" {
+		return ""
+	}
+
+	return DefaultJVMMaxMemory
+}
@@ -0,0 +1,51 @@
+package bfmetadata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestToolDirectory(t *testing.T) {
+	installFakeJava(t, `echo "$*"`)
+
+	tests := map[string]func(t *testing.T, dir string) *Client{
+		"WithTempDir": func(t *testing.T, dir string) *Client {
+			return NewClient(WithTempDir(dir))
+		},
+		"BF_TOOL_DIR": func(t *testing.T, dir string) *Client {
+			t.Setenv("BF_TOOL_DIR", dir)
+			return NewClient()
+		},
+	}
+	for name, newClient := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "tools")
+			c := newClient(t, dir)
+
+			out, err := c.PrintHelp(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for filename := range platformScripts(runtime.GOOS) {
+				if _, err := os.Stat(filepath.Join(dir, filename)); err != nil {
+					t.Errorf("%s not extracted: %v", filename, err)
+				}
+			}
+			jar := filepath.Join(dir, "bioformats_package.jar")
+			if _, err := os.Stat(jar); err != nil {
+				t.Errorf("jar not extracted: %v", err)
+			}
+			if !strings.Contains(out, "-cp "+dir+":") || !strings.Contains(out, jar) {
+				t.Errorf("tool not run from %s, java arguments: %q", dir, out)
+			}
+			if !strings.Contains(out, "loci.formats.tools.ImageConverter --help") {
+				t.Errorf("bfconvert not launched, java arguments: %q", out)
+			}
+		})
+	}
+}
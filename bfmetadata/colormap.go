@@ -0,0 +1,109 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// colormapSize is the number of entries in every colormap returned by GetColormap.
+const colormapSize = 256
+
+// GetColormap returns a 256-entry RGB lookup table for a channel. The table
+// ramps from black to the channel Color when one is set, otherwise to a
+// pseudocolor derived from the emission (or excitation) wavelength of
+// fluorescence channels. Other channels get a grayscale ramp.
+func GetColormap(ome *OME, seriesIdx, channelIdx int) ([][3]uint8, error) {
+	channel, err := getChannel(ome, seriesIdx, channelIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	if channel.Color != "" {
+		rgb, err := parseOMEColor(channel.Color)
+		if err != nil {
+			return nil, err
+		}
+		return colorRamp(rgb), nil
+	}
+
+	if isFluorescenceChannel(*channel) {
+		wavelength, unit := channel.EmissionWavelength, channel.EmissionWavelengthUnit
+		if wavelength <= 0 {
+			wavelength, unit = channel.ExcitationWavelength, channel.ExcitationWavelengthUnit
+		}
+		if wavelength > 0 {
+			nm, err := wavelengthNm(wavelength, unit)
+			if err != nil {
+				return nil, err
+			}
+			return colorRamp(wavelengthToRGB(nm)), nil
+		}
+	}
+
+	return colorRamp([3]uint8{255, 255, 255}), nil
+}
+
+// parseOMEColor decodes an OME Color attribute, a signed 32-bit RGBA integer.
+func parseOMEColor(value string) ([3]uint8, error) {
+	c, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return [3]uint8{}, fmt.Errorf("error parsing channel color %q: %w", value, err)
+	}
+
+	rgba := uint32(c)
+	return [3]uint8{uint8(rgba >> 24), uint8(rgba >> 16), uint8(rgba >> 8)}, nil
+}
+
+// isFluorescenceChannel reports whether a channel looks like a fluorescence channel.
+func isFluorescenceChannel(channel Channel) bool {
+	if channel.ContrastMethod != "" {
+		return channel.ContrastMethod == ContrastMethodFluorescence
+	}
+
+	switch channel.IlluminationType {
+	case "Transmitted", "Oblique":
+		return false
+	case "Epifluorescence":
+		return true
+	}
+
+	return channel.Fluor != "" || channel.EmissionWavelength > 0 || channel.ExcitationWavelength > 0
+}
+
+// colorRamp returns a linear ramp from black to rgb.
+func colorRamp(rgb [3]uint8) [][3]uint8 {
+	ramp := make([][3]uint8, colormapSize)
+	for i := range ramp {
+		for k := 0; k < 3; k++ {
+			ramp[i][k] = uint8(int(rgb[k]) * i / (colormapSize - 1))
+		}
+	}
+
+	return ramp
+}
+
+// wavelengthToRGB approximates the visible color of a wavelength in nanometers.
+// Wavelengths outside the visible range are clamped to its ends.
+func wavelengthToRGB(nm float64) [3]uint8 {
+	nm = math.Max(380, math.Min(nm, 780))
+
+	var r, g, b float64
+	switch {
+	case nm < 440:
+		r, b = (440-nm)/(440-380), 1
+	case nm < 490:
+		g, b = (nm-440)/(490-440), 1
+	case nm < 510:
+		g, b = 1, (510-nm)/(510-490)
+	case nm < 580:
+		r, g = (nm-510)/(580-510), 1
+	case nm < 645:
+		r, g = 1, (645-nm)/(645-580)
+	default:
+		r = 1
+	}
+
+	return [3]uint8{uint8(math.Round(r * 255)), uint8(math.Round(g * 255)), uint8(math.Round(b * 255))}
+}
@@ -0,0 +1,57 @@
+package bfmetadata
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// companionSuffix is appended to an image file name to form its companion OME-XML path.
+const companionSuffix = ".companion.ome"
+
+// GetOMEXMLFromCompanionFile reads OME-XML directly from a companion file
+// without invoking Bio-Formats.
+func GetOMEXMLFromCompanionFile(companionPath string) (string, error) {
+	data, err := os.ReadFile(companionPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", &FileNotFoundError{Path: companionPath, Err: err}
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading companion file %s: %w", companionPath, err)
+	}
+
+	content := string(data)
+	if !strings.Contains(content, "<OME") {
+		return "", fmt.Errorf("no OME-XML content found in companion file %s", companionPath)
+	}
+
+	return content, nil
+}
+
+// FindCompanionFile reports whether <imageFilePath>.companion.ome exists next
+// to the image and returns its path.
+func FindCompanionFile(imageFilePath string) (string, bool) {
+	companionPath := imageFilePath + companionSuffix
+
+	info, err := os.Stat(companionPath)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	return companionPath, true
+}
+
+// ParseOMEXMLFile parses an OME-XML file on disk, such as a saved showinf
+// dump or a companion .ome file, without invoking Bio-Formats.
+func ParseOMEXMLFile(xmlFilePath string) (*OME, error) {
+	data, err := os.ReadFile(xmlFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &FileNotFoundError{Path: xmlFilePath, Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading OME-XML file %s: %w", xmlFilePath, err)
+	}
+
+	return parseXML(string(data))
+}
@@ -0,0 +1,103 @@
+package bfmetadata
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ConvertOptions selects what bfconvert writes. Series and the T and Z range
+// fields are nil for "no restriction", so the zero value converts every
+// series and plane.
+type ConvertOptions struct {
+	Series      *int
+	Compression string
+	TileSizeX   int
+	TileSizeY   int
+	FirstT      *int
+	LastT       *int
+	FirstZ      *int
+	LastZ       *int
+	Overwrite   bool
+}
+
+// DefaultConvertOptions returns options converting every series and plane.
+// It is the same as the zero value.
+func DefaultConvertOptions() ConvertOptions {
+	return ConvertOptions{}
+}
+
+// ConvertFile is a wrapper around the default Client's ConvertFile.
+func ConvertFile(ctx context.Context, src, dst string, opts ConvertOptions) error {
+	return defaultClient.ConvertFile(ctx, src, dst, opts)
+}
+
+// ConvertFile converts src to dst with bfconvert, which picks the output
+// format from the extension of dst. Progress lines are logged at info level
+// while the conversion runs.
+func (c *Client) ConvertFile(ctx context.Context, src, dst string, opts ConvertOptions) error {
+	if err := checkInputFile(src); err != nil {
+		return err
+	}
+
+	args, err := convertArgs(opts)
+	if err != nil {
+		return err
+	}
+	args = append(args, src, dst)
+
+	_, _, err = c.run(ctx, "bfconvert", nil, args, func(line string) {
+		c.logf(LogLevelInfo, "bfconvert: %s", line)
+	})
+	return err
+}
+
+// convertArgs translates options into bfconvert flags. bfconvert can only
+// restrict T and Z to a single index, so a range must start and end at the
+// same plane.
+func convertArgs(opts ConvertOptions) ([]string, error) {
+	var args []string
+	if opts.Overwrite {
+		args = append(args, "-overwrite")
+	} else {
+		args = append(args, "-nooverwrite")
+	}
+	if opts.Series != nil {
+		if *opts.Series < 0 {
+			return nil, fmt.Errorf("invalid series index %d", *opts.Series)
+		}
+		args = append(args, "-series", strconv.Itoa(*opts.Series))
+	}
+	if opts.Compression != "" {
+		args = append(args, "-compression", opts.Compression)
+	}
+	if opts.TileSizeX > 0 {
+		args = append(args, "-tilex", strconv.Itoa(opts.TileSizeX))
+	}
+	if opts.TileSizeY > 0 {
+		args = append(args, "-tiley", strconv.Itoa(opts.TileSizeY))
+	}
+
+	ranges := []struct {
+		name        string
+		flag        string
+		first, last *int
+	}{{"T", "-timepoint", opts.FirstT, opts.LastT}, {"Z", "-z", opts.FirstZ, opts.LastZ}}
+	for _, r := range ranges {
+		if r.first == nil && r.last == nil {
+			continue
+		}
+		if r.first == nil || r.last == nil {
+			return nil, fmt.Errorf("bfconvert %s range needs both a first and a last index", r.name)
+		}
+		if *r.first != *r.last {
+			return nil, fmt.Errorf("bfconvert cannot select %s range %d-%d; only a single %s index is supported", r.name, *r.first, *r.last, r.name)
+		}
+		if *r.first < 0 {
+			return nil, fmt.Errorf("invalid %s index %d", r.name, *r.first)
+		}
+		args = append(args, r.flag, strconv.Itoa(*r.first))
+	}
+
+	return args, nil
+}
@@ -0,0 +1,48 @@
+package bfmetadata
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertArgs(t *testing.T) {
+	series, z, otherZ := 2, 3, 4
+	tests := []struct {
+		name    string
+		opts    ConvertOptions
+		want    string
+		wantErr bool
+	}{
+		{"zero value", ConvertOptions{}, "-nooverwrite", false},
+		{"defaults", DefaultConvertOptions(), "-nooverwrite", false},
+		{"compression only", ConvertOptions{Compression: "LZW", Overwrite: true}, "-overwrite -compression LZW", false},
+		{"series and plane", ConvertOptions{Series: &series, FirstZ: &z, LastZ: &z}, "-nooverwrite -series 2 -z 3", false},
+		{"series 0", ConvertOptions{Series: new(int)}, "-nooverwrite -series 0", false},
+		{"Z range", ConvertOptions{FirstZ: &z, LastZ: &otherZ}, "", true},
+		{"open Z range", ConvertOptions{FirstZ: &z}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := convertArgs(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got := strings.Join(args, " "); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertArgsZeroValueSelectsEverything(t *testing.T) {
+	args, err := convertArgs(ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range args {
+		switch arg {
+		case "-series", "-timepoint", "-z":
+			t.Errorf("zero value restricts output with %s: %v", arg, args)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package bfmetadata
+
+import (
+	"strings"
+	"unicode"
+)
+
+// GetOMEXMLCreatorInfo parses the Creator attribute of the root OME element
+// into software name and version, and returns the Created or LastModified
+// attribute as timestamp when present. Creator strings of the form
+// "Software (version)" and "Software version" are both recognised.
+func GetOMEXMLCreatorInfo(xmlData string) (software, version, timestamp string, err error) {
+	root, err := rootElement(xmlData)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var creator string
+	for _, attr := range root.Attr {
+		switch attr.Name.Local {
+		case "Creator":
+			creator = strings.TrimSpace(attr.Value)
+		case "Created":
+			timestamp = attr.Value
+		case "LastModified":
+			if timestamp == "" {
+				timestamp = attr.Value
+			}
+		}
+	}
+
+	software, version = splitCreator(creator)
+	return software, version, timestamp, nil
+}
+
+// splitCreator separates the software name from its version.
+func splitCreator(creator string) (string, string) {
+	if open := strings.LastIndex(creator, "("); open != -1 && strings.HasSuffix(creator, ")") {
+		return strings.TrimSpace(creator[:open]), strings.TrimSpace(creator[open+1 : len(creator)-1])
+	}
+
+	if space := strings.LastIndex(creator, " "); space != -1 {
+		last := creator[space+1:]
+		if digits := strings.TrimPrefix(last, "v"); digits != "" && unicode.IsDigit(rune(digits[0])) {
+			return creator[:space], last
+		}
+	}
+
+	return creator, ""
+}
@@ -0,0 +1,213 @@
+package bfmetadata
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+
+	_ "modernc.org/sqlite"
+)
+
+// MetadataReport holds the parsed OME metadata extracted from one image file.
+type MetadataReport struct {
+	FilePath string
+	OME      *OME
+}
+
+// catalogSchema creates the tables used by ExportMetadataToDB. Physical
+// sizes are stored in µm; every other dimensional value has a unit column
+// holding the OME default when the document omits the unit.
+const catalogSchema = `
+CREATE TABLE IF NOT EXISTS files (
+	id   INTEGER PRIMARY KEY AUTOINCREMENT,
+	path TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS series (
+	file_id          INTEGER NOT NULL REFERENCES files(id),
+	series_index     INTEGER NOT NULL,
+	image_id         TEXT,
+	name             TEXT,
+	acquisition_date TEXT,
+	dimension_order  TEXT,
+	pixel_type       TEXT,
+	significant_bits INTEGER,
+	size_x           INTEGER,
+	size_y           INTEGER,
+	size_z           INTEGER,
+	size_c           INTEGER,
+	size_t           INTEGER,
+	physical_size_x_um REAL,
+	physical_size_y_um REAL,
+	physical_size_z_um REAL,
+	PRIMARY KEY (file_id, series_index)
+);
+CREATE TABLE IF NOT EXISTS channels (
+	file_id               INTEGER NOT NULL REFERENCES files(id),
+	series_index          INTEGER NOT NULL,
+	channel_index         INTEGER NOT NULL,
+	channel_id            TEXT,
+	name                  TEXT,
+	samples_per_pixel     INTEGER,
+	illumination_type     TEXT,
+	excitation_wavelength      REAL,
+	excitation_wavelength_unit TEXT,
+	emission_wavelength        REAL,
+	emission_wavelength_unit   TEXT,
+	fluor                      TEXT,
+	PRIMARY KEY (file_id, series_index, channel_index)
+);
+CREATE TABLE IF NOT EXISTS planes (
+	file_id       INTEGER NOT NULL REFERENCES files(id),
+	series_index  INTEGER NOT NULL,
+	plane_index   INTEGER NOT NULL,
+	the_z         INTEGER,
+	the_c         INTEGER,
+	the_t         INTEGER,
+	delta_t            REAL,
+	delta_t_unit       TEXT,
+	exposure_time      REAL,
+	exposure_time_unit TEXT,
+	position_x         REAL,
+	position_x_unit    TEXT,
+	position_y         REAL,
+	position_y_unit    TEXT,
+	position_z         REAL,
+	position_z_unit    TEXT,
+	PRIMARY KEY (file_id, series_index, plane_index)
+);
+`
+
+// ExportMetadataToDB writes the given reports to a SQLite catalog at dbPath,
+// creating the files, series, channels and planes tables if necessary. All
+// rows are inserted in a single transaction.
+func ExportMetadataToDB(results []MetadataReport, dbPath string) error {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return fmt.Errorf("error opening database %s: %w", dbPath, err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(catalogSchema); err != nil {
+		return fmt.Errorf("error creating database schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertReports(tx, results); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing metadata: %w", err)
+	}
+
+	return nil
+}
+
+// insertReports inserts every report using prepared statements on tx.
+func insertReports(tx *sql.Tx, results []MetadataReport) error {
+	fileStmt, err := tx.Prepare(`INSERT INTO files (path) VALUES (?)`)
+	if err != nil {
+		return fmt.Errorf("error preparing file insert: %w", err)
+	}
+	defer fileStmt.Close()
+
+	seriesStmt, err := tx.Prepare(`INSERT INTO series VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("error preparing series insert: %w", err)
+	}
+	defer seriesStmt.Close()
+
+	channelStmt, err := tx.Prepare(`INSERT INTO channels VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("error preparing channel insert: %w", err)
+	}
+	defer channelStmt.Close()
+
+	planeStmt, err := tx.Prepare(`INSERT INTO planes VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("error preparing plane insert: %w", err)
+	}
+	defer planeStmt.Close()
+
+	for _, result := range results {
+		res, err := fileStmt.Exec(result.FilePath)
+		if err != nil {
+			return fmt.Errorf("error inserting file %s: %w", result.FilePath, err)
+		}
+		fileID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error reading file id for %s: %w", result.FilePath, err)
+		}
+
+		if result.OME == nil {
+			continue
+		}
+
+		for s, image := range result.OME.Images {
+			p := image.Pixels
+			_, err := seriesStmt.Exec(fileID, s, image.ID, image.Name, image.AcquisitionDateRaw,
+				p.DimensionOrder, string(p.Type), p.SignificantBits,
+				p.SizeX, p.SizeY, p.SizeZ, p.SizeC, p.SizeT,
+				sizeInMicrons(p.PhysicalSizeX, p.PhysicalSizeXRaw, p.PhysicalSizeXUnit),
+				sizeInMicrons(p.PhysicalSizeY, p.PhysicalSizeYRaw, p.PhysicalSizeYUnit),
+				sizeInMicrons(p.PhysicalSizeZ, p.PhysicalSizeZRaw, p.PhysicalSizeZUnit))
+			if err != nil {
+				return fmt.Errorf("error inserting series %d of %s: %w", s, result.FilePath, err)
+			}
+
+			for c, channel := range p.Channels {
+				_, err := channelStmt.Exec(fileID, s, c, channel.ID, channel.Name,
+					channel.SamplesPerPixel, channel.IlluminationType,
+					channel.ExcitationWavelength, unitOrDefault(channel.ExcitationWavelengthUnit, "nm"),
+					channel.EmissionWavelength, unitOrDefault(channel.EmissionWavelengthUnit, "nm"),
+					channel.Fluor)
+				if err != nil {
+					return fmt.Errorf("error inserting channel %d of series %d: %w", c, s, err)
+				}
+			}
+
+			for i, plane := range p.Planes {
+				_, err := planeStmt.Exec(fileID, s, i, plane.TheZ, plane.TheC, plane.TheT,
+					plane.DeltaT, unitOrDefault(plane.DeltaTUnit, "s"),
+					plane.ExposureTime, unitOrDefault(plane.ExposureTimeUnit, "s"),
+					plane.PositionX, unitOrDefault(plane.PositionXUnit, "reference frame"),
+					plane.PositionY, unitOrDefault(plane.PositionYUnit, "reference frame"),
+					plane.PositionZ, unitOrDefault(plane.PositionZUnit, "reference frame"))
+				if err != nil {
+					return fmt.Errorf("error inserting plane %d of series %d: %w", i, s, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// sizeInMicrons returns a physical size converted to µm for storage, or nil
+// when the size is unset or its unit is not a known length unit.
+func sizeInMicrons(size float64, raw, unit string) interface{} {
+	if raw == "" {
+		return nil
+	}
+
+	microns := NormaliseToMicrons(size, unit)
+	if math.IsNaN(microns) {
+		return nil
+	}
+
+	return microns
+}
+
+// unitOrDefault returns unit, or the OME default unit def when it is empty.
+func unitOrDefault(unit, def string) string {
+	if unit == "" {
+		return def
+	}
+
+	return unit
+}
@@ -0,0 +1,62 @@
+package bfmetadata
+
+import (
+	"database/sql"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportMetadataToDB(t *testing.T) {
+	ome, err := parseXML(`<OME xmlns="http://www.openmicroscopy.org/Schemas/OME/2016-06">
+  <Image ID="Image:0" Name="a">
+    <Pixels ID="Pixels:0" DimensionOrder="XYZCT" Type="uint16" SizeX="4" SizeY="4" SizeZ="1" SizeC="1" SizeT="1"
+        PhysicalSizeX="65" PhysicalSizeXUnit="nm" PhysicalSizeY="0.065">
+      <Channel ID="Channel:0:0" EmissionWavelength="0.52" EmissionWavelengthUnit="µm" ExcitationWavelength="488"/>
+      <Plane TheZ="0" TheC="0" TheT="0" DeltaT="250" DeltaTUnit="ms" PositionX="1.5" PositionXUnit="mm"/>
+    </Pixels>
+  </Image>
+</OME>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbPath := filepath.Join(t.TempDir(), "catalog.db")
+	if err := ExportMetadataToDB([]MetadataReport{{FilePath: "a.tif", OME: ome}}, dbPath); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var x, y float64
+	var z sql.NullFloat64
+	err = db.QueryRow(`SELECT physical_size_x_um, physical_size_y_um, physical_size_z_um FROM series`).Scan(&x, &y, &z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x-0.065) > 1e-12 || y != 0.065 || z.Valid {
+		t.Errorf("got sizes %g, %g, %v µm, want 0.065, 0.065, NULL", x, y, z)
+	}
+
+	var excitationUnit, emissionUnit string
+	err = db.QueryRow(`SELECT excitation_wavelength_unit, emission_wavelength_unit FROM channels`).Scan(&excitationUnit, &emissionUnit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if excitationUnit != "nm" || emissionUnit != "µm" {
+		t.Errorf("got wavelength units %q, %q, want nm, µm", excitationUnit, emissionUnit)
+	}
+
+	var deltaTUnit, exposureUnit, positionXUnit, positionYUnit string
+	err = db.QueryRow(`SELECT delta_t_unit, exposure_time_unit, position_x_unit, position_y_unit FROM planes`).Scan(&deltaTUnit, &exposureUnit, &positionXUnit, &positionYUnit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deltaTUnit != "ms" || exposureUnit != "s" || positionXUnit != "mm" || positionYUnit != "reference frame" {
+		t.Errorf("got plane units %q, %q, %q, %q", deltaTUnit, exposureUnit, positionXUnit, positionYUnit)
+	}
+}
@@ -0,0 +1,51 @@
+package bfmetadata
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotEMCCDDetector is returned by GetEMGain when the channel's detector
+// is not an EMCCD.
+var ErrNotEMCCDDetector = errors.New("channel detector is not an EMCCD")
+
+// GetEMGain returns the electron multiplication gain of a channel. For EMCCD
+// detectors DetectorSettings.Gain holds the EM gain rather than analog gain,
+// so a value is only returned when the referenced detector's Type is EMCCD.
+func GetEMGain(ome *OME, seriesIdx, channelIdx int) (float64, error) {
+	channel, err := getChannel(ome, seriesIdx, channelIdx)
+	if err != nil {
+		return 0, err
+	}
+	if channel.DetectorSettings == nil || channel.DetectorSettings.ID == "" {
+		return 0, fmt.Errorf("channel %s has no detector settings", channel.ID)
+	}
+
+	detector, err := getDetector(ome, &ome.Images[seriesIdx], channel.DetectorSettings.ID)
+	if err != nil {
+		return 0, err
+	}
+	if !strings.EqualFold(detector.Type, "EMCCD") {
+		return 0, ErrNotEMCCDDetector
+	}
+
+	return channel.DetectorSettings.Gain, nil
+}
+
+// getDetector resolves a detector ID within the instrument referenced by image.
+func getDetector(ome *OME, image *Image, id string) (*Detector, error) {
+	for i := range ome.Instruments {
+		instrument := &ome.Instruments[i]
+		if image.InstrumentRef != nil && image.InstrumentRef.ID != instrument.ID {
+			continue
+		}
+		for j := range instrument.Detectors {
+			if instrument.Detectors[j].ID == id {
+				return &instrument.Detectors[j], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("detector %s referenced by image %s not found", id, image.ID)
+}
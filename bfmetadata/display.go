@@ -0,0 +1,178 @@
+package bfmetadata
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrDisplayRangeNotSet is returned by GetChannelDisplayRange when no
+// display range is recorded for a channel.
+var ErrDisplayRangeNotSet = errors.New("channel display range not set")
+
+// Keys used by acquisition software to record channel display ranges.
+var (
+	displayRangeMinKeys = []string{"DisplayRangeMin", "DisplayMin", "RangeMin"}
+	displayRangeMaxKeys = []string{"DisplayRangeMax", "DisplayMax", "RangeMax"}
+	contrastMinKeys     = []string{"ContrastLimitMin", "ContrastMin", "WindowStart", "Min"}
+	contrastMaxKeys     = []string{"ContrastLimitMax", "ContrastMax", "WindowEnd", "Max"}
+)
+
+// ChannelRenderingHint holds the display settings acquisition software
+// suggests for a channel.
+type ChannelRenderingHint struct {
+	ChannelIndex int
+	Color        [3]uint8
+	DisplayMin   float64
+	DisplayMax   float64
+}
+
+// GetSoftwareRenderingHints is a wrapper around the default Client's GetSoftwareRenderingHints.
+func GetSoftwareRenderingHints(ctx context.Context, filePath string) ([]ChannelRenderingHint, error) {
+	return defaultClient.GetSoftwareRenderingHints(ctx, filePath)
+}
+
+// GetSoftwareRenderingHints returns the display hints embedded by the
+// acquisition software for the channels of the first series of filePath.
+// Only channels with a Color or a display range recorded are included; a
+// channel without a Color is reported as white and one without a display
+// range has DisplayMin and DisplayMax 0.
+func (c *Client) GetSoftwareRenderingHints(ctx context.Context, filePath string) ([]ChannelRenderingHint, error) {
+	metadata, err := c.getParsedMetadata(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderingHints(metadata, 0)
+}
+
+// renderingHints collects the rendering hints of the channels of a series.
+func renderingHints(ome *OME, seriesIdx int) ([]ChannelRenderingHint, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	var hints []ChannelRenderingHint
+	for i, channel := range image.Pixels.Channels {
+		hint := ChannelRenderingHint{ChannelIndex: i, Color: [3]uint8{255, 255, 255}}
+
+		hasColor := channel.Color != ""
+		if hasColor {
+			if hint.Color, err = parseOMEColor(channel.Color); err != nil {
+				return nil, err
+			}
+		}
+
+		min, max, err := GetChannelDisplayRange(ome, seriesIdx, i)
+		hasRange := err == nil
+		if err != nil && !errors.Is(err, ErrDisplayRangeNotSet) {
+			return nil, err
+		}
+		hint.DisplayMin, hint.DisplayMax = min, max
+
+		if hasColor || hasRange {
+			hints = append(hints, hint)
+		}
+	}
+
+	return hints, nil
+}
+
+// GetCustomAttributes converts non-OME attributes captured on an element
+// to a map keyed by local attribute name.
+func GetCustomAttributes(attrs []xml.Attr) map[string]string {
+	custom := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		if isNamespaceDecl(attr) {
+			continue
+		}
+		custom[attr.Name.Local] = attr.Value
+	}
+
+	return custom
+}
+
+// GetChannelDisplayRange returns the display range of a channel. Custom
+// channel attributes such as DisplayRangeMin and DisplayRangeMax take
+// precedence, followed by GetChannelContrastLimits. ErrDisplayRangeNotSet is
+// returned when neither source has data.
+func GetChannelDisplayRange(ome *OME, seriesIdx, channelIdx int) (min, max float64, err error) {
+	channel, err := getChannel(ome, seriesIdx, channelIdx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	custom := GetCustomAttributes(channel.CustomAttributes)
+	if min, max, ok, err := rangeFromValues(custom, displayRangeMinKeys, displayRangeMaxKeys); ok || err != nil {
+		return min, max, err
+	}
+
+	min, max, err = GetChannelContrastLimits(ome, seriesIdx, channelIdx)
+	if err != nil {
+		return 0, 0, ErrDisplayRangeNotSet
+	}
+
+	return min, max, nil
+}
+
+// GetChannelContrastLimits returns contrast limits recorded in map
+// annotations linked to a channel.
+func GetChannelContrastLimits(ome *OME, seriesIdx, channelIdx int) (min, max float64, err error) {
+	channel, err := getChannel(ome, seriesIdx, channelIdx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	values := make(map[string]string)
+	for _, annotation := range linkedMapAnnotations(ome, channel.AnnotationRefs) {
+		for _, pair := range annotation.Values {
+			values[pair.Key] = pair.Value
+		}
+	}
+
+	min, max, ok, err := rangeFromValues(values, contrastMinKeys, contrastMaxKeys)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !ok {
+		return 0, 0, fmt.Errorf("no contrast limits found for channel %d of series %d", channelIdx, seriesIdx)
+	}
+
+	return min, max, nil
+}
+
+// rangeFromValues looks up a min/max pair using the first key of each list
+// present in values. ok is false unless both ends are found.
+func rangeFromValues(values map[string]string, minKeys, maxKeys []string) (min, max float64, ok bool, err error) {
+	minValue, minOK := firstValue(values, minKeys)
+	maxValue, maxOK := firstValue(values, maxKeys)
+	if !minOK || !maxOK {
+		return 0, 0, false, nil
+	}
+
+	min, err = strconv.ParseFloat(strings.TrimSpace(minValue), 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("error parsing range minimum %q: %w", minValue, err)
+	}
+	max, err = strconv.ParseFloat(strings.TrimSpace(maxValue), 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("error parsing range maximum %q: %w", maxValue, err)
+	}
+
+	return min, max, true, nil
+}
+
+// firstValue returns the value of the first key present in values.
+func firstValue(values map[string]string, keys []string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
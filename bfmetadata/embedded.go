@@ -0,0 +1,32 @@
+package bfmetadata
+
+import (
+	"encoding/hex"
+	"sort"
+)
+
+// EmbeddedFile describes one Bio-Formats tool file embedded in the package.
+type EmbeddedFile struct {
+	Name   string
+	Size   int64
+	SHA256 string
+}
+
+// GetEmbeddedFileList returns the embedded tool files sorted by name, with
+// their sizes and hex-encoded SHA-256 digests.
+func GetEmbeddedFileList() []EmbeddedFile {
+	files := toolFiles()
+
+	list := make([]EmbeddedFile, 0, len(files))
+	for name, data := range files {
+		sum := embeddedHashes[name]
+		list = append(list, EmbeddedFile{
+			Name:   name,
+			Size:   int64(len(data)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
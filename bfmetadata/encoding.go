@@ -0,0 +1,59 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// xmlEncodingPattern matches the encoding declaration of an XML prolog.
+var xmlEncodingPattern = regexp.MustCompile(`^\s*<\?xml[^>]*?\sencoding\s*=\s*["']([^"']+)["']`)
+
+// GetXMLEncoding returns the encoding declared in the XML prolog of xmlData,
+// or "UTF-8" when none is declared.
+func GetXMLEncoding(xmlData string) string {
+	if m := xmlEncodingPattern.FindStringSubmatch(xmlData); m != nil {
+		return m[1]
+	}
+
+	return "UTF-8"
+}
+
+// TranscodeOMEXML converts OME-XML whose bytes are in fromEncoding, e.g.
+// "ISO-8859-1", to UTF-8 and updates the encoding declaration accordingly.
+// Encoding names are resolved as in the WHATWG encoding standard.
+func TranscodeOMEXML(xmlData, fromEncoding string) (string, error) {
+	enc, err := htmlindex.Get(fromEncoding)
+	if err != nil {
+		return "", fmt.Errorf("unsupported encoding %q: %w", fromEncoding, err)
+	}
+
+	decoded, err := enc.NewDecoder().String(xmlData)
+	if err != nil {
+		return "", fmt.Errorf("error transcoding from %s: %w", fromEncoding, err)
+	}
+
+	if m := xmlEncodingPattern.FindStringSubmatchIndex(decoded); m != nil {
+		decoded = decoded[:m[2]] + "UTF-8" + decoded[m[3]:]
+	}
+
+	return decoded, nil
+}
+
+// charsetReader converts XML input in a declared non-UTF-8 encoding to
+// UTF-8 for xml.Decoder.
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	if strings.EqualFold(label, "UTF-8") {
+		return input, nil
+	}
+
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported encoding %q: %w", label, err)
+	}
+
+	return enc.NewDecoder().Reader(input), nil
+}
@@ -0,0 +1,89 @@
+package bfmetadata
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// ToolExecutionError is returned when a Bio-Formats tool could not be run or
+// exited unsuccessfully. ExitCode is -1 when the process did not exit
+// normally, e.g. because it could not be started or was killed. Version is
+// the embedded Bio-Formats version, if known.
+type ToolExecutionError struct {
+	Tool     string
+	Version  string
+	ExitCode int
+	Stderr   string
+	Err      error
+}
+
+func (e *ToolExecutionError) Error() string {
+	tool := e.Tool
+	if e.Version != "" {
+		tool = fmt.Sprintf("%s (Bio-Formats %s)", e.Tool, e.Version)
+	}
+
+	return fmt.Sprintf("error executing %s: %v, stderr: %s", tool, e.Err, e.Stderr)
+}
+
+func (e *ToolExecutionError) Unwrap() error { return e.Err }
+
+// XMLParseError is returned when OME-XML cannot be decoded. Snippet holds the
+// XML surrounding the point where decoding failed.
+type XMLParseError struct {
+	Snippet string
+	Err     error
+}
+
+func (e *XMLParseError) Error() string {
+	return fmt.Sprintf("error parsing XML near %q: %v", e.Snippet, e.Err)
+}
+
+func (e *XMLParseError) Unwrap() error { return e.Err }
+
+// FileNotFoundError is returned when an input file does not exist.
+type FileNotFoundError struct {
+	Path string
+	Err  error
+}
+
+func (e *FileNotFoundError) Error() string {
+	return fmt.Sprintf("file not found: %s", e.Path)
+}
+
+func (e *FileNotFoundError) Unwrap() error { return e.Err }
+
+// newToolExecutionError wraps the error returned by running tool.
+func newToolExecutionError(tool, stderr string, err error) *ToolExecutionError {
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+
+	version, _ := GetBioFormatsVersion()
+
+	return &ToolExecutionError{Tool: tool, Version: version, ExitCode: exitCode, Stderr: stderr, Err: err}
+}
+
+// checkInputFile returns a FileNotFoundError when path does not exist.
+func checkInputFile(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return &FileNotFoundError{Path: path, Err: err}
+	}
+
+	return nil
+}
+
+// newXMLParseError wraps a decoding error that occurred at offset in data.
+func newXMLParseError(data []byte, offset int64, err error) *XMLParseError {
+	const window = 40
+
+	start := max(offset-window, 0)
+	end := min(offset+window, int64(len(data)))
+	start = min(start, end)
+
+	return &XMLParseError{Snippet: string(data[start:end]), Err: err}
+}
@@ -0,0 +1,39 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetExperimentDescription returns the free-text Description of the
+// experiment at experimentIdx.
+func GetExperimentDescription(ome *OME, experimentIdx int) (string, error) {
+	if ome == nil {
+		return "", fmt.Errorf("no OME metadata provided")
+	}
+	if experimentIdx < 0 || experimentIdx >= len(ome.Experiments) {
+		return "", fmt.Errorf("experiment index %d out of range (%d experiments)", experimentIdx, len(ome.Experiments))
+	}
+
+	return strings.TrimSpace(ome.Experiments[experimentIdx].Description), nil
+}
+
+// GetImageExperiment returns the experiment referenced by the ExperimentRef
+// of the image with the given ID.
+func GetImageExperiment(ome *OME, imageID string) (Experiment, error) {
+	image, err := getImageByID(ome, imageID)
+	if err != nil {
+		return Experiment{}, err
+	}
+	if image.ExperimentRef == nil {
+		return Experiment{}, fmt.Errorf("image %s has no ExperimentRef", imageID)
+	}
+
+	for _, experiment := range ome.Experiments {
+		if experiment.ID == image.ExperimentRef.ID {
+			return experiment, nil
+		}
+	}
+
+	return Experiment{}, fmt.Errorf("experiment %s referenced by image %s not found", image.ExperimentRef.ID, imageID)
+}
@@ -0,0 +1,35 @@
+package bfmetadata
+
+import "context"
+
+// GetExperimenters is a wrapper around the default Client's GetExperimenters.
+func GetExperimenters(ctx context.Context, filePath string) ([]Experimenter, error) {
+	return defaultClient.GetExperimenters(ctx, filePath)
+}
+
+// GetExperimenters returns the Experimenter elements declared in the
+// OME-XML metadata of filePath, in document order.
+func (c *Client) GetExperimenters(ctx context.Context, filePath string) ([]Experimenter, error) {
+	metadata, err := c.getParsedMetadata(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata.Experimenters, nil
+}
+
+// getImageExperimenter returns the experimenter referenced by the image's
+// ExperimenterRef, or nil if it has none or the reference does not resolve.
+func getImageExperimenter(ome *OME, image *Image) *Experimenter {
+	if image.ExperimenterRef == nil {
+		return nil
+	}
+
+	for i := range ome.Experimenters {
+		if ome.Experimenters[i].ID == image.ExperimenterRef.ID {
+			return &ome.Experimenters[i]
+		}
+	}
+
+	return nil
+}
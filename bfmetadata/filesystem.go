@@ -0,0 +1,50 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileSystem is the subset of file operations used to extract the embedded
+// tools, so extraction can be exercised without touching the disk.
+type fileSystem interface {
+	MkdirAll(path string, perm os.FileMode) error
+	Stat(path string) (os.FileInfo, error)
+	WriteFile(path string, data []byte, perm os.FileMode) error
+	ReadFile(path string) ([]byte, error)
+}
+
+// osFileSystem implements fileSystem on the real file system.
+type osFileSystem struct{}
+
+func (osFileSystem) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+
+func (osFileSystem) Stat(path string) (os.FileInfo, error) { return os.Stat(path) }
+
+func (osFileSystem) ReadFile(path string) ([]byte, error) { return os.ReadFile(path) }
+
+// WriteFile writes data under a temporary name and renames it into place,
+// so other processes sharing the directory never see a partial file.
+func (osFileSystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp file for %s: %w", path, err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return nil
+}
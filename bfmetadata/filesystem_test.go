@@ -0,0 +1,132 @@
+package bfmetadata
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memFileSystem is an in-memory fileSystem. Writes to paths in failWrites
+// return that error.
+type memFileSystem struct {
+	files      map[string]memFile
+	dirs       map[string]bool
+	writes     []string
+	failWrites map[string]error
+}
+
+type memFile struct {
+	data []byte
+	perm os.FileMode
+}
+
+func newMemFileSystem() *memFileSystem {
+	return &memFileSystem{files: make(map[string]memFile), dirs: make(map[string]bool), failWrites: make(map[string]error)}
+}
+
+func (m *memFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	m.dirs[path] = true
+	return nil
+}
+
+func (m *memFileSystem) Stat(name string) (os.FileInfo, error) {
+	if m.dirs[name] {
+		return memFileInfo{name: path.Base(name), mode: fs.ModeDir | 0755}, nil
+	}
+	if f, ok := m.files[name]; ok {
+		return memFileInfo{name: path.Base(name), size: int64(len(f.data)), mode: f.perm}, nil
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *memFileSystem) ReadFile(name string) ([]byte, error) {
+	if f, ok := m.files[name]; ok {
+		return f.data, nil
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *memFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if err := m.failWrites[name]; err != nil {
+		return err
+	}
+	m.files[name] = memFile{data: data, perm: perm}
+	m.writes = append(m.writes, path.Base(name))
+	return nil
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+	mode os.FileMode
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() os.FileMode  { return i.mode }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memFileInfo) Sys() interface{}   { return nil }
+
+func TestPrepareFilesSkipsMatchingFiles(t *testing.T) {
+	fsys := newMemFileSystem()
+	dir := "/tools"
+	if _, err := prepareFiles(fsys, dir); err != nil {
+		t.Fatal(err)
+	}
+	if !fsys.dirs[dir] {
+		t.Errorf("%s not created", dir)
+	}
+
+	fsys.writes = nil
+	if _, err := prepareFiles(fsys, dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(fsys.writes) != 0 {
+		t.Errorf("rewrote matching files %v", fsys.writes)
+	}
+}
+
+func TestPrepareFilesOverwritesMismatchedFiles(t *testing.T) {
+	fsys := newMemFileSystem()
+	dir := "/tools"
+	if _, err := prepareFiles(fsys, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	jar := filepath.Join(dir, "bioformats_package.jar")
+	fsys.files[jar] = memFile{data: bioformatsJar[:1024], perm: 0644}
+	fsys.writes = nil
+
+	if _, err := prepareFiles(fsys, dir); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(fsys.writes, ",") != "bioformats_package.jar" {
+		t.Errorf("wrote %v, want only the truncated jar", fsys.writes)
+	}
+	if len(fsys.files[jar].data) != len(bioformatsJar) {
+		t.Errorf("jar has %d bytes after extraction, want %d", len(fsys.files[jar].data), len(bioformatsJar))
+	}
+}
+
+func TestPrepareFilesReportsWriteErrors(t *testing.T) {
+	fsys := newMemFileSystem()
+	dir := "/tools"
+	errFull := errors.New("no space left on device")
+	fsys.failWrites[filepath.Join(dir, "bioformats_package.jar")] = errFull
+
+	_, err := prepareFiles(fsys, dir)
+	if !errors.Is(err, errFull) {
+		t.Fatalf("got error %v, want %v", err, errFull)
+	}
+	if !strings.Contains(err.Error(), "bioformats_package.jar") {
+		t.Errorf("error %q does not name the file", err)
+	}
+}
@@ -0,0 +1,128 @@
+package bfmetadata
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Fluorophore describes the spectral peaks of a known fluorophore.
+type Fluorophore struct {
+	Name             string
+	ExcitationPeakNm float64
+	EmissionPeakNm   float64
+}
+
+// Embed fluorophores.json
+//
+//go:embed data/fluorophores.json
+var fluorophoresJSON []byte
+
+// emissionTolerance is the largest difference in nanometers between a
+// channel's emission wavelength and its fluorophore's emission peak that is
+// accepted by GetValidatedChannelFluorophores.
+const emissionTolerance = 40
+
+var (
+	fluorophoreOnce  sync.Once
+	fluorophoreIndex map[string]Fluorophore
+	fluorophoreErr   error
+)
+
+// LookupFluorophore finds a fluorophore by name or alias. The lookup ignores
+// case, spaces and punctuation, so "GFP", "EGFP" and "Enhanced GFP" match
+// the same entry.
+func LookupFluorophore(name string) (Fluorophore, error) {
+	fluorophoreOnce.Do(loadFluorophores)
+	if fluorophoreErr != nil {
+		return Fluorophore{}, fluorophoreErr
+	}
+
+	f, ok := fluorophoreIndex[fluorophoreKey(name)]
+	if !ok {
+		return Fluorophore{}, fmt.Errorf("unknown fluorophore %q", name)
+	}
+
+	return f, nil
+}
+
+// GetValidatedChannelFluorophores looks up the Fluor of every channel in a
+// series. The returned slice has one entry per channel; channels whose
+// fluorophore is missing or unknown, or whose emission wavelength is far from
+// the fluorophore's emission peak, produce an error and a zero Fluorophore.
+func GetValidatedChannelFluorophores(ome *OME, seriesIdx int) ([]Fluorophore, []error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	fluorophores := make([]Fluorophore, len(image.Pixels.Channels))
+	var errs []error
+
+	for i, channel := range image.Pixels.Channels {
+		if channel.Fluor == "" {
+			errs = append(errs, fmt.Errorf("channel %d has no fluorophore", i))
+			continue
+		}
+
+		f, err := LookupFluorophore(channel.Fluor)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %d: %w", i, err))
+			continue
+		}
+
+		if channel.EmissionWavelength > 0 {
+			emission, err := wavelengthNm(channel.EmissionWavelength, channel.EmissionWavelengthUnit)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("channel %d: %w", i, err))
+				continue
+			}
+			if math.Abs(emission-f.EmissionPeakNm) > emissionTolerance {
+				errs = append(errs, fmt.Errorf("channel %d: emission wavelength %.0f nm does not match %s emission peak %.0f nm", i, emission, f.Name, f.EmissionPeakNm))
+				continue
+			}
+		}
+
+		fluorophores[i] = f
+	}
+
+	return fluorophores, errs
+}
+
+// loadFluorophores builds the lookup index from the embedded database.
+func loadFluorophores() {
+	var entries []struct {
+		Name             string   `json:"name"`
+		Aliases          []string `json:"aliases"`
+		ExcitationPeakNm float64  `json:"excitationPeakNm"`
+		EmissionPeakNm   float64  `json:"emissionPeakNm"`
+	}
+
+	if err := json.Unmarshal(fluorophoresJSON, &entries); err != nil {
+		fluorophoreErr = fmt.Errorf("error parsing embedded fluorophore database: %w", err)
+		return
+	}
+
+	fluorophoreIndex = make(map[string]Fluorophore)
+	for _, entry := range entries {
+		f := Fluorophore{Name: entry.Name, ExcitationPeakNm: entry.ExcitationPeakNm, EmissionPeakNm: entry.EmissionPeakNm}
+		fluorophoreIndex[fluorophoreKey(entry.Name)] = f
+		for _, alias := range entry.Aliases {
+			fluorophoreIndex[fluorophoreKey(alias)] = f
+		}
+	}
+}
+
+// fluorophoreKey normalises a fluorophore name for lookup.
+func fluorophoreKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,155 @@
+package bfmetadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrUnsupportedFormat is returned by DetectFormat when no Bio-Formats
+// reader recognises the file.
+var ErrUnsupportedFormat = errors.New("file format not supported by Bio-Formats")
+
+// FormatInfo describes a file format supported by Bio-Formats.
+type FormatInfo struct {
+	Name       string
+	Extensions []string
+	CanWrite   bool
+}
+
+// formatLinePattern matches a plaintext line of the Bio-Formats format
+// table, e.g. "Zeiss CZI: can read (czi)".
+var formatLinePattern = regexp.MustCompile(`^(.+?): can read(, can write)?(?:, can write multiple)? \((.*)\)$`)
+
+// GetSupportedFormats is a wrapper around the default Client's GetSupportedFormats.
+func GetSupportedFormats(ctx context.Context) ([]FormatInfo, error) {
+	return defaultClient.GetSupportedFormats(ctx)
+}
+
+// GetSupportedFormats lists the formats the embedded Bio-Formats can read,
+// as printed by its formatlist tool.
+func (c *Client) GetSupportedFormats(ctx context.Context) ([]FormatInfo, error) {
+	stdout, stderr, err := c.runProgram(ctx, "loci.formats.tools.PrintFormatTable", "-txt")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFormatTable(stdout + "\n" + stderr), nil
+}
+
+// parseFormatTable parses the plaintext output of PrintFormatTable.
+func parseFormatTable(output string) []FormatInfo {
+	var formats []FormatInfo
+	for _, line := range strings.Split(output, "\n") {
+		m := formatLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		format := FormatInfo{Name: m[1], CanWrite: m[2] != ""}
+		for _, ext := range strings.Split(m[3], ",") {
+			if ext = strings.TrimSpace(ext); ext != "" {
+				format.Extensions = append(format.Extensions, ext)
+			}
+		}
+		formats = append(formats, format)
+	}
+
+	return formats
+}
+
+// FormatFamily groups file formats by vendor or container.
+type FormatFamily string
+
+const (
+	FormatFamilyUnknown FormatFamily = ""
+	FormatFamilyTIFF    FormatFamily = "TIFF"
+	FormatFamilyHDF5    FormatFamily = "HDF5"
+	FormatFamilyZeiss   FormatFamily = "Zeiss"
+	FormatFamilyLeica   FormatFamily = "Leica"
+	FormatFamilyNikon   FormatFamily = "Nikon"
+	FormatFamilyOlympus FormatFamily = "Olympus"
+	FormatFamilyOME     FormatFamily = "OME"
+)
+
+// formatFamilies maps lower-case extensions without the leading dot to their family.
+var formatFamilies = map[string]FormatFamily{
+	"tif":           FormatFamilyTIFF,
+	"tiff":          FormatFamilyTIFF,
+	"tf2":           FormatFamilyTIFF,
+	"tf8":           FormatFamilyTIFF,
+	"btf":           FormatFamilyTIFF,
+	"svs":           FormatFamilyTIFF,
+	"ndpi":          FormatFamilyTIFF,
+	"h5":            FormatFamilyHDF5,
+	"hdf":           FormatFamilyHDF5,
+	"hdf5":          FormatFamilyHDF5,
+	"ims":           FormatFamilyHDF5,
+	"czi":           FormatFamilyZeiss,
+	"lsm":           FormatFamilyZeiss,
+	"zvi":           FormatFamilyZeiss,
+	"lif":           FormatFamilyLeica,
+	"lof":           FormatFamilyLeica,
+	"lei":           FormatFamilyLeica,
+	"scn":           FormatFamilyLeica,
+	"xlef":          FormatFamilyLeica,
+	"nd2":           FormatFamilyNikon,
+	"oib":           FormatFamilyOlympus,
+	"oif":           FormatFamilyOlympus,
+	"oir":           FormatFamilyOlympus,
+	"vsi":           FormatFamilyOlympus,
+	"ome":           FormatFamilyOME,
+	"ome.tif":       FormatFamilyOME,
+	"ome.tiff":      FormatFamilyOME,
+	"ome.tf2":       FormatFamilyOME,
+	"ome.tf8":       FormatFamilyOME,
+	"ome.btf":       FormatFamilyOME,
+	"ome.xml":       FormatFamilyOME,
+	"ome.zarr":      FormatFamilyOME,
+	"companion.ome": FormatFamilyOME,
+}
+
+// GetFormatFamily returns the format family of a file extension such as
+// ".czi" or "ome.tif". Matching ignores case and a leading dot, and
+// FormatFamilyUnknown is returned for unrecognised extensions.
+func GetFormatFamily(extension string) FormatFamily {
+	return formatFamilies[strings.ToLower(strings.TrimPrefix(extension, "."))]
+}
+
+// DetectFormat is a wrapper around the default Client's DetectFormat.
+func DetectFormat(ctx context.Context, filePath string) (string, error) {
+	return defaultClient.DetectFormat(ctx, filePath)
+}
+
+// DetectFormat runs showinf -nopix -noflat against filePath and returns the
+// short class name of the reader Bio-Formats picked, e.g. "LIFReader". A
+// file no reader recognises yields an error wrapping ErrUnsupportedFormat.
+func (c *Client) DetectFormat(ctx context.Context, filePath string) (string, error) {
+	if err := checkInputFile(filePath); err != nil {
+		return "", err
+	}
+
+	stdout, stderr, err := c.runTool(ctx, "showinf", filePath, "-nopix", "-noflat")
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	output := stdout + "\n" + stderr
+	if strings.Contains(output, "UnknownFormatException") || strings.Contains(output, "Unknown file format") {
+		return "", fmt.Errorf("%s: %w", filePath, ErrUnsupportedFormat)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := readerPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			reader := m[1]
+			return reader[strings.LastIndex(reader, ".")+1:], nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: %w", filePath, ErrUnsupportedFormat)
+}
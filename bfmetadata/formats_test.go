@@ -0,0 +1,59 @@
+package bfmetadata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installFakeJava puts a java executable running script on PATH so that the
+// extracted launcher scripts can be exercised without a JVM.
+func installFakeJava(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("launcher scripts are only tested on Unix")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "java"), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunProgram(t *testing.T) {
+	installFakeJava(t, `echo "args: $*"
+echo "Zeiss CZI: can read (czi)"
+echo "Tagged Image File Format: can read, can write, can write multiple (tif, tiff)"
+`)
+
+	c := NewClient(WithTempDir(t.TempDir()))
+
+	stdout, stderr, err := c.runProgram(context.Background(), "loci.formats.tools.PrintFormatTable", "-txt")
+	if err != nil {
+		t.Fatalf("runProgram: %v (stderr: %s)", err, stderr)
+	}
+	if !strings.Contains(stdout, "loci.formats.tools.PrintFormatTable -txt") {
+		t.Errorf("class and arguments not passed to java, stdout: %q", stdout)
+	}
+	if !strings.Contains(stdout, "-Xmx"+DefaultJVMMaxMemory) {
+		t.Errorf("heap size not passed to java, stdout: %q", stdout)
+	}
+
+	formats, err := c.GetSupportedFormats(context.Background())
+	if err != nil {
+		t.Fatalf("GetSupportedFormats: %v", err)
+	}
+	if len(formats) != 2 {
+		t.Fatalf("got %d formats, want 2: %+v", len(formats), formats)
+	}
+	if formats[0].Name != "Zeiss CZI" || formats[0].CanWrite {
+		t.Errorf("formats[0] = %+v", formats[0])
+	}
+	if !formats[1].CanWrite || strings.Join(formats[1].Extensions, ",") != "tif,tiff" {
+		t.Errorf("formats[1] = %+v", formats[1])
+	}
+}
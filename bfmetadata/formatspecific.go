@@ -0,0 +1,490 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatSpecificMetadata is implemented by typed views over the raw
+// metadata of a particular file format.
+type FormatSpecificMetadata interface {
+	// GetRawKeys returns the sorted raw metadata keys that belong to the format.
+	GetRawKeys() []string
+}
+
+// CZIMetadata provides typed access to Zeiss CZI raw metadata. The exported
+// fields are filled by GetCZIMetadata and NewCZIMetadata.
+type CZIMetadata struct {
+	AcquisitionDate time.Time
+	Author          string
+	ExperimentName  string
+	ScanFrameTime   float64
+	ScanZoom        float64
+
+	raw map[string]string
+}
+
+// ND2Metadata provides typed access to Nikon ND2 raw metadata. The exported
+// fields are filled by GetND2Metadata and NewND2Metadata.
+type ND2Metadata struct {
+	PixelCalibration    float64
+	CalibrationUnit     string
+	ObjectiveName       string
+	ObjectiveNA         float64
+	TubeLensFocalLength float64
+	ZStep               float64
+	PinholeRadius       float64
+
+	raw map[string]string
+}
+
+// LIFMetadata provides typed access to Leica LIF raw metadata. The exported
+// fields are filled by GetLIFMetadata and NewLIFMetadata.
+type LIFMetadata struct {
+	SequentialMode string
+	ZDriveMode     string
+	TileScanMode   string
+	ScanMode       string
+	Zoom           float64
+	LineAverage    int
+
+	raw map[string]string
+}
+
+// LSMMetadata holds well-known Zeiss LSM raw metadata parameters. The
+// per-channel maps are keyed by the raw key prefix identifying the channel,
+// e.g. "IlluminationChannel #1".
+type LSMMetadata struct {
+	LaserPowerPercent   map[string]float64
+	PinholeDiameterAiry map[string]float64
+	ScanSpeed           int
+	ScanMode            string
+	AveragingMethod     string
+	AveragingCount      int
+
+	raw map[string]string
+}
+
+// SVSMetadata holds the Aperio SVS slide parameters stored as key-value
+// pairs in the ImageDescription TIFF tag.
+type SVSMetadata struct {
+	AppMag       int
+	StripeWidth  int
+	StripeHeight int
+	MPP          float64
+	Date         string
+	Time         string
+	ScanScopeID  string
+	Filename     string
+
+	raw map[string]string
+}
+
+// Raw metadata key markers used to recognise each format's keys.
+var (
+	cziKeyMarkers = []string{"Experiment|", "Information|", "HardwareSetting|"}
+	nd2KeyMarkers = []string{"dCalibration", "dObjective", "sObjective", "dZStep", "dPinholeRadius"}
+	lifKeyMarkers = []string{"Experiment/", "ATLConfocalSettingDefinition", "ATLCameraSettingDefinition", "TileScanInfo"}
+	svsKeyMarkers = []string{"AppMag", "StripeWidth", "StripeHeight", "MPP", "ScanScope ID", "ImageDescription"}
+	lsmKeyMarkers = []string{"Recording", "IlluminationChannel", "DetectionChannel", "Track #", "Laser #"}
+)
+
+// Known CZI raw metadata keys. Bio-Formats appends " #1" to keys that may
+// occur more than once.
+var (
+	cziAcquisitionDateKeys = []string{"Information|Image|AcquisitionDateAndTime #1", "Information|Image|AcquisitionDateAndTime", "Information|Document|CreationDate #1", "Information|Document|CreationDate"}
+	cziAuthorKeys          = []string{"Information|Document|UserName #1", "Information|Document|UserName", "Information|User|DisplayName #1", "Information|User|DisplayName"}
+	cziExperimentNameKeys  = []string{"Information|Document|Name #1", "Information|Document|Name", "Information|Document|Title #1", "Information|Document|Title"}
+	cziScanFrameTimeKeys   = []string{"LaserScanInfo|FrameTime #1", "LaserScanInfo|FrameTime"}
+	cziScanZoomKeys        = []string{"LaserScanInfo|ZoomX #1", "LaserScanInfo|ZoomX"}
+)
+
+// Known ND2 raw metadata keys.
+var (
+	nd2CalibrationKeys         = []string{"dCalibration"}
+	nd2ObjectiveNameKeys       = []string{"wsObjectiveName", "sObjective"}
+	nd2ObjectiveNAKeys         = []string{"dObjectiveNA"}
+	nd2TubeLensFocalLengthKeys = []string{"dTubeLensFocalLength", "TubeLensFocalLength"}
+	nd2ZStepKeys               = []string{"dZStep"}
+	nd2PinholeRadiusKeys       = []string{"dPinholeRadius"}
+)
+
+// Known LIF raw metadata keys.
+var (
+	lifSequentialModeKeys = []string{"ATLConfocalSettingDefinition|SequentialMode", "SequentialMode"}
+	lifZDriveModeKeys     = []string{"ATLConfocalSettingDefinition|ZUseMode", "ZDriveMode", "ZUseMode"}
+	lifTileScanModeKeys   = []string{"TileScanInfo|Mode", "TileScanMode"}
+	lifScanModeKeys       = []string{"ATLConfocalSettingDefinition|ScanMode", "ATLCameraSettingDefinition|ScanMode"}
+	lifZoomKeys           = []string{"ATLConfocalSettingDefinition|Zoom"}
+	lifLineAverageKeys    = []string{"ATLConfocalSettingDefinition|LineAverage"}
+)
+
+// Known LSM raw metadata keys.
+var (
+	lsmLaserPowerMarkers   = []string{"IlluminationChannel", "Laser"}
+	lsmLaserPowerSuffixes  = []string{"Power (%)", "Power"}
+	lsmPinholeMarkers      = []string{"DetectionChannel"}
+	lsmPinholeSuffixes     = []string{"Pinhole Diameter (Airy)", "Pinhole Airy", "PinholeAiry"}
+	lsmScanSpeedKeys       = []string{"Scan Speed", "ScanSpeed"}
+	lsmScanModeKeys        = []string{"Scan Mode", "ScanMode"}
+	lsmAveragingMethodKeys = []string{"Averaging Method", "AveragingMethod"}
+	lsmAveragingCountKeys  = []string{"Number of Averages", "Averaging Number", "AveragingCount"}
+)
+
+// NewCZIMetadata wraps raw metadata returned for a CZI file. Unlike
+// GetCZIMetadata it does not fail on malformed values; fields whose value
+// cannot be parsed keep their zero value.
+func NewCZIMetadata(rawMetadata map[string]string) CZIMetadata {
+	m, _ := parseCZIMetadata(rawMetadata)
+	return m
+}
+
+// NewND2Metadata wraps raw metadata returned for an ND2 file. Unlike
+// GetND2Metadata it does not fail on malformed values; fields whose value
+// cannot be parsed keep their zero value.
+func NewND2Metadata(rawMetadata map[string]string) ND2Metadata {
+	m, _ := parseND2Metadata(rawMetadata)
+	return m
+}
+
+// NewLIFMetadata wraps raw metadata returned for a LIF file. Unlike
+// GetLIFMetadata it does not fail on malformed values; fields whose value
+// cannot be parsed keep their zero value.
+func NewLIFMetadata(rawMetadata map[string]string) LIFMetadata {
+	m, _ := parseLIFMetadata(rawMetadata)
+	return m
+}
+
+func (m CZIMetadata) GetRawKeys() []string { return rawKeysContaining(m.raw, cziKeyMarkers) }
+
+func (m ND2Metadata) GetRawKeys() []string { return rawKeysContaining(m.raw, nd2KeyMarkers) }
+
+func (m LIFMetadata) GetRawKeys() []string { return rawKeysContaining(m.raw, lifKeyMarkers) }
+
+func (m LSMMetadata) GetRawKeys() []string { return rawKeysContaining(m.raw, lsmKeyMarkers) }
+
+func (m SVSMetadata) GetRawKeys() []string { return rawKeysContaining(m.raw, svsKeyMarkers) }
+
+// GetSVSMetadata parses the Aperio SVS slide parameters from raw metadata.
+// Bio-Formats exposes them both as separate keys and inside the raw
+// ImageDescription, whose "|"-separated "Key = Value" pairs are used for
+// keys that are not present on their own. Parameters that are not found
+// keep their zero value.
+func GetSVSMetadata(rawMetadata map[string]string) (SVSMetadata, error) {
+	values := make(map[string]string)
+	if _, description, ok := findRawValue(rawMetadata, []string{"ImageDescription"}); ok {
+		for key, value := range parseSVSDescription(description) {
+			values[key] = value
+		}
+	}
+	for key, value := range rawMetadata {
+		values[key] = value
+	}
+
+	// Keys such as Date or Time are too generic for suffix matching, so only
+	// exact keys are used.
+	m := SVSMetadata{
+		Date:        values["Date"],
+		Time:        values["Time"],
+		ScanScopeID: values["ScanScope ID"],
+		Filename:    values["Filename"],
+		raw:         rawMetadata,
+	}
+
+	floats := []struct {
+		key string
+		set func(float64)
+	}{
+		// AppMag is occasionally written with a fractional part, e.g. "20.0".
+		{"AppMag", func(f float64) { m.AppMag = int(f) }},
+		{"StripeWidth", func(f float64) { m.StripeWidth = int(f) }},
+		{"StripeHeight", func(f float64) { m.StripeHeight = int(f) }},
+		{"MPP", func(f float64) { m.MPP = f }},
+	}
+	for _, field := range floats {
+		value, ok := values[field.key]
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return SVSMetadata{}, fmt.Errorf("error parsing %s: %w", field.key, err)
+		}
+		field.set(f)
+	}
+
+	return m, nil
+}
+
+// parseSVSDescription splits an Aperio ImageDescription into its key-value
+// pairs. The first "|"-separated field describes the image pyramid and is
+// skipped.
+func parseSVSDescription(description string) map[string]string {
+	values := make(map[string]string)
+	fields := strings.Split(description, "|")
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return values
+}
+
+// GetCZIMetadata parses the well-known Zeiss CZI keys from raw metadata.
+// Parameters without a matching key keep their zero value.
+func GetCZIMetadata(rawMetadata map[string]string) (CZIMetadata, error) {
+	m, err := parseCZIMetadata(rawMetadata)
+	if err != nil {
+		return CZIMetadata{}, err
+	}
+
+	return m, nil
+}
+
+// parseCZIMetadata fills every CZI field that parses and returns the first
+// parse error alongside the partially filled metadata.
+func parseCZIMetadata(rawMetadata map[string]string) (CZIMetadata, error) {
+	m := CZIMetadata{raw: rawMetadata}
+
+	var firstErr error
+	if key, value, ok := findRawValue(rawMetadata, cziAcquisitionDateKeys); ok {
+		if t, err := parseAcquisitionDate(value); err != nil {
+			firstErr = fmt.Errorf("error parsing %s: %w", key, err)
+		} else {
+			m.AcquisitionDate = t
+		}
+	}
+	if err := parseRawFloats(rawMetadata, []rawFloatField{
+		{&m.ScanFrameTime, cziScanFrameTimeKeys},
+		{&m.ScanZoom, cziScanZoomKeys},
+	}); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	_, m.Author, _ = findRawValue(rawMetadata, cziAuthorKeys)
+	_, m.ExperimentName, _ = findRawValue(rawMetadata, cziExperimentNameKeys)
+
+	return m, firstErr
+}
+
+// GetND2Metadata parses the well-known Nikon ND2 keys from raw metadata.
+// ND2 files store the pixel calibration in micrometers per pixel, so
+// CalibrationUnit is "µm" whenever PixelCalibration is set. Parameters
+// without a matching key keep their zero value.
+func GetND2Metadata(rawMetadata map[string]string) (ND2Metadata, error) {
+	m, err := parseND2Metadata(rawMetadata)
+	if err != nil {
+		return ND2Metadata{}, err
+	}
+
+	return m, nil
+}
+
+// parseND2Metadata fills every ND2 field that parses and returns the first
+// parse error alongside the partially filled metadata.
+func parseND2Metadata(rawMetadata map[string]string) (ND2Metadata, error) {
+	m := ND2Metadata{raw: rawMetadata}
+
+	err := parseRawFloats(rawMetadata, []rawFloatField{
+		{&m.PixelCalibration, nd2CalibrationKeys},
+		{&m.ObjectiveNA, nd2ObjectiveNAKeys},
+		{&m.TubeLensFocalLength, nd2TubeLensFocalLengthKeys},
+		{&m.ZStep, nd2ZStepKeys},
+		{&m.PinholeRadius, nd2PinholeRadiusKeys},
+	})
+	if m.PixelCalibration != 0 {
+		m.CalibrationUnit = "µm"
+	}
+	_, m.ObjectiveName, _ = findRawValue(rawMetadata, nd2ObjectiveNameKeys)
+
+	return m, err
+}
+
+// GetLIFMetadata parses the well-known Leica LIF keys from raw metadata.
+// Parameters without a matching key keep their zero value.
+func GetLIFMetadata(rawMetadata map[string]string) (LIFMetadata, error) {
+	m, err := parseLIFMetadata(rawMetadata)
+	if err != nil {
+		return LIFMetadata{}, err
+	}
+
+	return m, nil
+}
+
+// parseLIFMetadata fills every LIF field that parses and returns the first
+// parse error alongside the partially filled metadata.
+func parseLIFMetadata(rawMetadata map[string]string) (LIFMetadata, error) {
+	m := LIFMetadata{raw: rawMetadata}
+
+	firstErr := parseRawFloats(rawMetadata, []rawFloatField{{&m.Zoom, lifZoomKeys}})
+	if lineAverage, err := optionalRawInt(rawMetadata, lifLineAverageKeys); err != nil {
+		if firstErr == nil {
+			firstErr = err
+		}
+	} else {
+		m.LineAverage = lineAverage
+	}
+	_, m.SequentialMode, _ = findRawValue(rawMetadata, lifSequentialModeKeys)
+	_, m.ZDriveMode, _ = findRawValue(rawMetadata, lifZDriveModeKeys)
+	_, m.TileScanMode, _ = findRawValue(rawMetadata, lifTileScanModeKeys)
+	_, m.ScanMode, _ = findRawValue(rawMetadata, lifScanModeKeys)
+
+	return m, firstErr
+}
+
+// GetLSMMetadata parses the well-known Zeiss LSM keys from raw metadata.
+// Parameters without a matching key keep their zero value.
+func GetLSMMetadata(rawMetadata map[string]string) (LSMMetadata, error) {
+	m := LSMMetadata{raw: rawMetadata}
+
+	var err error
+	if m.LaserPowerPercent, err = rawFloatsBySuffix(rawMetadata, lsmLaserPowerMarkers, lsmLaserPowerSuffixes); err != nil {
+		return LSMMetadata{}, err
+	}
+	if m.PinholeDiameterAiry, err = rawFloatsBySuffix(rawMetadata, lsmPinholeMarkers, lsmPinholeSuffixes); err != nil {
+		return LSMMetadata{}, err
+	}
+	if m.ScanSpeed, err = optionalRawInt(rawMetadata, lsmScanSpeedKeys); err != nil {
+		return LSMMetadata{}, err
+	}
+	if m.AveragingCount, err = optionalRawInt(rawMetadata, lsmAveragingCountKeys); err != nil {
+		return LSMMetadata{}, err
+	}
+	_, m.ScanMode, _ = findRawValue(rawMetadata, lsmScanModeKeys)
+	_, m.AveragingMethod, _ = findRawValue(rawMetadata, lsmAveragingMethodKeys)
+
+	return m, nil
+}
+
+// Calibration returns the ND2 pixel calibration in micrometers per pixel.
+// It returns PixelCalibration when that is set and otherwise reports why the
+// calibration could not be read from the raw metadata.
+func (m ND2Metadata) Calibration() (float64, error) {
+	if m.PixelCalibration != 0 {
+		return m.PixelCalibration, nil
+	}
+
+	return lookupRawFloat(m.raw, nd2CalibrationKeys)
+}
+
+// MultiTrackSetup returns the CZI multi-track acquisition setup value.
+func (m CZIMetadata) MultiTrackSetup() (string, bool) {
+	_, value, ok := findRawValue(m.raw, []string{"Experiment|AcquisitionBlock|MultiTrackSetup"})
+	return value, ok
+}
+
+// ExperimentKeys returns the LIF experiment keys and their values.
+func (m LIFMetadata) ExperimentKeys() map[string]string {
+	experiment := make(map[string]string)
+	for _, key := range rawKeysContaining(m.raw, []string{"Experiment/"}) {
+		experiment[key] = m.raw[key]
+	}
+
+	return experiment
+}
+
+// rawKeysContaining returns the sorted keys containing any of the markers.
+func rawKeysContaining(rawMetadata map[string]string, markers []string) []string {
+	var keys []string
+	for key := range rawMetadata {
+		for _, marker := range markers {
+			if strings.Contains(key, marker) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// rawFloatsBySuffix parses every key containing one of the markers and
+// ending in one of the suffixes, keyed by the key with the suffix removed.
+func rawFloatsBySuffix(rawMetadata map[string]string, markers, suffixes []string) (map[string]float64, error) {
+	values := make(map[string]float64)
+	for _, key := range rawKeysContaining(rawMetadata, markers) {
+		for _, suffix := range suffixes {
+			if !strings.HasSuffix(key, suffix) {
+				continue
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(rawMetadata[key]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s: %w", key, err)
+			}
+			values[strings.TrimSpace(strings.TrimSuffix(key, suffix))] = f
+			break
+		}
+	}
+
+	return values, nil
+}
+
+// rawFloatField pairs a float destination with the raw keys it is read from.
+type rawFloatField struct {
+	dst  *float64
+	keys []string
+}
+
+// parseRawFloats parses every field with optionalRawFloat. Fields that fail
+// to parse keep their zero value and the first error is returned.
+func parseRawFloats(rawMetadata map[string]string, fields []rawFloatField) error {
+	var firstErr error
+	for _, f := range fields {
+		v, err := optionalRawFloat(rawMetadata, f.keys)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		*f.dst = v
+	}
+
+	return firstErr
+}
+
+// optionalRawFloat is like lookupRawFloat but returns 0, nil when no key matches.
+func optionalRawFloat(rawMetadata map[string]string, keys []string) (float64, error) {
+	if _, _, ok := findRawValue(rawMetadata, keys); !ok {
+		return 0, nil
+	}
+
+	return lookupRawFloat(rawMetadata, keys)
+}
+
+// optionalRawInt parses the first matching key as an integer, returning
+// 0, nil when no key matches.
+func optionalRawInt(rawMetadata map[string]string, keys []string) (int, error) {
+	key, value, ok := findRawValue(rawMetadata, keys)
+	if !ok {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s: %w", key, err)
+	}
+
+	return i, nil
+}
+
+// lookupRawFloat finds the first matching key and parses its value as a float.
+func lookupRawFloat(rawMetadata map[string]string, keys []string) (float64, error) {
+	key, value, ok := findRawValue(rawMetadata, keys)
+	if !ok {
+		return 0, fmt.Errorf("none of the keys %v found in raw metadata", keys)
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s: %w", key, err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,75 @@
+package bfmetadata
+
+import "testing"
+
+func TestNewND2Metadata(t *testing.T) {
+	raw := map[string]string{
+		"dCalibration":    "0.325",
+		"wsObjectiveName": "Plan Apo 20x",
+		"dObjectiveNA":    "0.75",
+		"dZStep":          "not a number",
+	}
+
+	m := NewND2Metadata(raw)
+	if m.PixelCalibration != 0.325 || m.CalibrationUnit != "µm" {
+		t.Errorf("calibration = %v %q, want 0.325 µm", m.PixelCalibration, m.CalibrationUnit)
+	}
+	if m.ObjectiveName != "Plan Apo 20x" || m.ObjectiveNA != 0.75 {
+		t.Errorf("objective = %q NA %v", m.ObjectiveName, m.ObjectiveNA)
+	}
+	if m.ZStep != 0 {
+		t.Errorf("ZStep = %v, want 0 for a malformed value", m.ZStep)
+	}
+	if _, err := GetND2Metadata(raw); err == nil {
+		t.Error("GetND2Metadata accepted a malformed dZStep")
+	}
+}
+
+func TestND2MetadataCalibration(t *testing.T) {
+	calibration, err := NewND2Metadata(map[string]string{"dCalibration": "0.65"}).Calibration()
+	if err != nil || calibration != 0.65 {
+		t.Errorf("Calibration() = %v, %v, want 0.65", calibration, err)
+	}
+
+	if _, err := NewND2Metadata(map[string]string{}).Calibration(); err == nil {
+		t.Error("Calibration() succeeded without dCalibration")
+	}
+}
+
+func TestNewCZIMetadata(t *testing.T) {
+	m := NewCZIMetadata(map[string]string{
+		"Information|Image|AcquisitionDateAndTime #1": "2021-03-04T05:06:07",
+		"Information|Document|UserName #1":            "alice",
+		"Information|Document|Name #1":                "timelapse",
+		"LaserScanInfo|FrameTime #1":                  "1.5",
+		"LaserScanInfo|ZoomX #1":                      "bad",
+	})
+
+	if m.AcquisitionDate.IsZero() || m.AcquisitionDate.Year() != 2021 {
+		t.Errorf("AcquisitionDate = %v", m.AcquisitionDate)
+	}
+	if m.Author != "alice" || m.ExperimentName != "timelapse" {
+		t.Errorf("author %q, experiment %q", m.Author, m.ExperimentName)
+	}
+	if m.ScanFrameTime != 1.5 || m.ScanZoom != 0 {
+		t.Errorf("frame time %v, zoom %v, want 1.5 and 0", m.ScanFrameTime, m.ScanZoom)
+	}
+}
+
+func TestNewLIFMetadata(t *testing.T) {
+	m := NewLIFMetadata(map[string]string{
+		"ATLConfocalSettingDefinition|SequentialMode": "Between Lines",
+		"ATLConfocalSettingDefinition|ZUseMode":       "z-galvo",
+		"TileScanInfo|Mode":                           "Mosaic",
+		"ATLConfocalSettingDefinition|ScanMode":       "xyzt",
+		"ATLConfocalSettingDefinition|Zoom":           "3",
+		"ATLConfocalSettingDefinition|LineAverage":    "2.5",
+	})
+
+	if m.SequentialMode != "Between Lines" || m.ZDriveMode != "z-galvo" || m.TileScanMode != "Mosaic" || m.ScanMode != "xyzt" {
+		t.Errorf("modes = %q %q %q %q", m.SequentialMode, m.ZDriveMode, m.TileScanMode, m.ScanMode)
+	}
+	if m.Zoom != 3 || m.LineAverage != 0 {
+		t.Errorf("zoom %v, line average %d, want 3 and 0", m.Zoom, m.LineAverage)
+	}
+}
@@ -0,0 +1,53 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GenerateMinimalOMEXML returns a minimal OME-XML document describing a
+// single image with the given dimensions, for use as a companion file or
+// OME-TIFF header. A physical size of 0 is omitted from the output. The
+// pixel data is declared with MetadataOnly.
+func GenerateMinimalOMEXML(sizeX, sizeY, sizeZ, sizeC, sizeT int, pixelType PixelType, physicalSizeX, physicalSizeY float64) (string, error) {
+	sizes := []struct {
+		name  string
+		value int
+	}{{"SizeX", sizeX}, {"SizeY", sizeY}, {"SizeZ", sizeZ}, {"SizeC", sizeC}, {"SizeT", sizeT}}
+	for _, size := range sizes {
+		if size.value < 1 {
+			return "", fmt.Errorf("%s must be positive, got %d", size.name, size.value)
+		}
+	}
+	if !validPixelTypes[pixelType] {
+		return "", fmt.Errorf("invalid pixel type %q", pixelType)
+	}
+	if physicalSizeX < 0 || physicalSizeY < 0 {
+		return "", fmt.Errorf("invalid physical pixel size %gx%g µm", physicalSizeX, physicalSizeY)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&b, `<OME xmlns="%s" xmlns:xsi="%s" xsi:schemaLocation="%s %s/ome.xsd">`+"\n",
+		omeNamespace2016, xsiNamespace, omeNamespace2016, omeNamespace2016)
+	b.WriteString(`  <Image ID="Image:0" Name="Image:0">` + "\n")
+	fmt.Fprintf(&b, `    <Pixels ID="Pixels:0" DimensionOrder="XYZCT" Type="%s" SizeX="%d" SizeY="%d" SizeZ="%d" SizeC="%d" SizeT="%d"`,
+		pixelType, sizeX, sizeY, sizeZ, sizeC, sizeT)
+	if physicalSizeX > 0 {
+		fmt.Fprintf(&b, ` PhysicalSizeX="%s" PhysicalSizeXUnit="µm"`, strconv.FormatFloat(physicalSizeX, 'g', -1, 64))
+	}
+	if physicalSizeY > 0 {
+		fmt.Fprintf(&b, ` PhysicalSizeY="%s" PhysicalSizeYUnit="µm"`, strconv.FormatFloat(physicalSizeY, 'g', -1, 64))
+	}
+	b.WriteString(">\n")
+	for c := 0; c < sizeC; c++ {
+		fmt.Fprintf(&b, `      <Channel ID="Channel:0:%d" SamplesPerPixel="1"/>`+"\n", c)
+	}
+	b.WriteString("      <MetadataOnly/>\n")
+	b.WriteString("    </Pixels>\n")
+	b.WriteString("  </Image>\n")
+	b.WriteString("</OME>\n")
+
+	return b.String(), nil
+}
@@ -0,0 +1,58 @@
+package bfmetadata
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+)
+
+// GetOMEXMLHash is a wrapper around the default Client's GetOMEXMLHash.
+func GetOMEXMLHash(ctx context.Context, filePath string, algorithm string) (string, error) {
+	return defaultClient.GetOMEXMLHash(ctx, filePath, algorithm)
+}
+
+// GetOMEXMLHash extracts the OME-XML of filePath and returns the hex digest
+// of its canonical form using algorithm "sha256" or "md5". Hashing the
+// canonical form keeps the fingerprint stable across whitespace and
+// attribute-order differences between Bio-Formats versions.
+func (c *Client) GetOMEXMLHash(ctx context.Context, filePath string, algorithm string) (string, error) {
+	var h hash.Hash
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "md5":
+		h = md5.New()
+	default:
+		return "", fmt.Errorf("unsupported hash algorithm %q", algorithm)
+	}
+
+	xmlData, err := c.GetOmexmlMetadata(ctx, filePath, AllSeries)
+	if err != nil {
+		return "", err
+	}
+
+	canonical, err := canonicalXML(xmlData)
+	if err != nil {
+		return "", fmt.Errorf("error canonicalising OME-XML: %w", err)
+	}
+
+	h.Write([]byte(canonical))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// GetCanonicalOMEXMLHash returns the hex SHA-256 digest of the canonical
+// form of xmlData: comments and namespace prefixes are dropped, attributes
+// are sorted and whitespace is normalised, so the hash only changes when the
+// metadata content does.
+func GetCanonicalOMEXMLHash(xmlData string) (string, error) {
+	canonical, err := canonicalXML(xmlData)
+	if err != nil {
+		return "", fmt.Errorf("error canonicalising OME-XML: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,262 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetPlateExternalIdentifier returns the ExternalIdentifier of a plate,
+// typically its barcode or assay identifier.
+func GetPlateExternalIdentifier(ome *OME, plateIdx int) (string, error) {
+	plate, err := getPlate(ome, plateIdx)
+	if err != nil {
+		return "", err
+	}
+
+	return plate.ExternalIdentifier, nil
+}
+
+// GetPlateStatus returns the Status attribute of a plate.
+func GetPlateStatus(ome *OME, plateIdx int) (string, error) {
+	plate, err := getPlate(ome, plateIdx)
+	if err != nil {
+		return "", err
+	}
+
+	return plate.Status, nil
+}
+
+// GetPlateNamingConvention combines the row and column naming conventions of
+// a plate into a single name such as "LetterNumber" (A01) or "NumberNumber" (1.1).
+func GetPlateNamingConvention(plate Plate) (string, error) {
+	row, err := namingConventionName(plate.RowNamingConvention)
+	if err != nil {
+		return "", fmt.Errorf("plate %s row naming: %w", plate.ID, err)
+	}
+	col, err := namingConventionName(plate.ColumnNamingConvention)
+	if err != nil {
+		return "", fmt.Errorf("plate %s column naming: %w", plate.ID, err)
+	}
+
+	return row + col, nil
+}
+
+// FormatWellAddress formats the zero-based row and column of a well using
+// the plate's naming convention, e.g. "B03" for LetterNumber or "2.3" for
+// NumberNumber. Letter-named columns are separated from the row with ".".
+func FormatWellAddress(plate Plate, row, col int) (string, error) {
+	if row < 0 || (plate.Rows > 0 && row >= plate.Rows) {
+		return "", fmt.Errorf("row %d out of range (%d rows)", row, plate.Rows)
+	}
+	if col < 0 || (plate.Columns > 0 && col >= plate.Columns) {
+		return "", fmt.Errorf("column %d out of range (%d columns)", col, plate.Columns)
+	}
+
+	convention, err := GetPlateNamingConvention(plate)
+	if err != nil {
+		return "", err
+	}
+
+	width := max(len(strconv.Itoa(plate.Columns)), 2)
+	switch convention {
+	case "LetterNumber":
+		return fmt.Sprintf("%s%0*d", wellLetters(row), width, col+1), nil
+	case "NumberNumber":
+		return fmt.Sprintf("%d.%d", row+1, col+1), nil
+	case "LetterLetter":
+		return wellLetters(row) + "." + wellLetters(col), nil
+	default:
+		return fmt.Sprintf("%d.%s", row+1, wellLetters(col)), nil
+	}
+}
+
+// namingConventionName maps an OME NamingConvention value to "Letter" or "Number".
+func namingConventionName(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "letter":
+		return "Letter", nil
+	case "number":
+		return "Number", nil
+	case "":
+		return "", fmt.Errorf("naming convention not set")
+	}
+
+	return "", fmt.Errorf("unknown naming convention %q", value)
+}
+
+// wellLetters converts a zero-based index to a letter label: 0 is A, 25 is Z
+// and 26 is AA.
+func wellLetters(index int) string {
+	label := ""
+	for index >= 0 {
+		label = string(rune('A'+index%26)) + label
+		index = index/26 - 1
+	}
+
+	return label
+}
+
+// HCSHierarchy is the screen, plate, well and field tree of an HCS dataset.
+// Wells of plates that no screen references are listed in StandaloneWells.
+type HCSHierarchy struct {
+	Screens         []ScreenNode
+	StandaloneWells []WellNode
+}
+
+type ScreenNode struct {
+	Screen
+	Plates []PlateNode
+}
+
+type PlateNode struct {
+	Plate
+	Wells []WellNode
+}
+
+type WellNode struct {
+	Well
+	Samples []WellSampleNode
+}
+
+// WellSampleNode is one field of a well. SeriesIndex is the index of the
+// image the field references, or -1 when it has no resolvable ImageRef.
+type WellSampleNode struct {
+	WellSample
+	SeriesIndex int
+}
+
+// GetHCSHierarchy builds the HCS container tree by following screen
+// PlateRef and well sample ImageRef links.
+func GetHCSHierarchy(ome *OME) HCSHierarchy {
+	var hierarchy HCSHierarchy
+	if ome == nil {
+		return hierarchy
+	}
+
+	seriesByID := make(map[string]int)
+	for i, image := range ome.Images {
+		seriesByID[image.ID] = i
+	}
+
+	plateNodes := make(map[string]PlateNode)
+	for _, plate := range ome.Plates {
+		plateNodes[plate.ID] = buildPlateNode(plate, seriesByID)
+	}
+
+	inScreen := make(map[string]bool)
+	for _, screen := range ome.Screens {
+		node := ScreenNode{Screen: screen}
+		for _, ref := range screen.PlateRefs {
+			if plateNode, ok := plateNodes[ref.ID]; ok {
+				node.Plates = append(node.Plates, plateNode)
+				inScreen[ref.ID] = true
+			}
+		}
+		hierarchy.Screens = append(hierarchy.Screens, node)
+	}
+
+	for _, plate := range ome.Plates {
+		if !inScreen[plate.ID] {
+			hierarchy.StandaloneWells = append(hierarchy.StandaloneWells, plateNodes[plate.ID].Wells...)
+		}
+	}
+
+	return hierarchy
+}
+
+// buildPlateNode builds the well and field nodes of a plate.
+func buildPlateNode(plate Plate, seriesByID map[string]int) PlateNode {
+	node := PlateNode{Plate: plate}
+	for _, well := range plate.Wells {
+		wellNode := WellNode{Well: well}
+		for _, sample := range well.WellSamples {
+			seriesIdx := -1
+			if sample.ImageRef != nil {
+				if idx, ok := seriesByID[sample.ImageRef.ID]; ok {
+					seriesIdx = idx
+				}
+			}
+			wellNode.Samples = append(wellNode.Samples, WellSampleNode{WellSample: sample, SeriesIndex: seriesIdx})
+		}
+		node.Wells = append(node.Wells, wellNode)
+	}
+
+	return node
+}
+
+// GetWellAddressForSeries finds the well sample whose ImageRef points at the
+// image of seriesIdx and returns the indices of its plate, well and sample.
+func GetWellAddressForSeries(ome *OME, seriesIdx int) (plateIdx, wellIdx, sampleIdx int, err error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return -1, -1, -1, err
+	}
+
+	for p, plate := range ome.Plates {
+		for w, well := range plate.Wells {
+			for s, sample := range well.WellSamples {
+				if sample.ImageRef != nil && sample.ImageRef.ID == image.ID {
+					return p, w, s, nil
+				}
+			}
+		}
+	}
+
+	return -1, -1, -1, fmt.Errorf("series %d is not referenced by any well sample", seriesIdx)
+}
+
+// GetSeriesIndexForWellAddress returns the series index of the image
+// referenced by a well sample, addressed by plate index, zero-based well row
+// and column, and the sample's index within the well.
+func GetSeriesIndexForWellAddress(ome *OME, plateIdx, wellRow, wellCol, sampleIdx int) (int, error) {
+	plate, err := getPlate(ome, plateIdx)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, well := range plate.Wells {
+		if well.Row != wellRow || well.Column != wellCol {
+			continue
+		}
+		if sampleIdx < 0 || sampleIdx >= len(well.WellSamples) {
+			return -1, fmt.Errorf("sample index %d out of range (%d samples in well %s)", sampleIdx, len(well.WellSamples), well.ID)
+		}
+
+		sample := well.WellSamples[sampleIdx]
+		if sample.ImageRef == nil {
+			return -1, fmt.Errorf("well sample %s has no image reference", sample.ID)
+		}
+		for i, image := range ome.Images {
+			if image.ID == sample.ImageRef.ID {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("image %s referenced by well sample %s not found", sample.ImageRef.ID, sample.ID)
+	}
+
+	return -1, fmt.Errorf("no well at row %d, column %d in plate %s", wellRow, wellCol, plate.ID)
+}
+
+// GetExperimentalConditions merges the key-value pairs of all map
+// annotations linked to a well, such as compound concentrations or
+// treatment times. Annotations are applied in AnnotationRef order, so a
+// later annotation overrides an earlier value for the same key.
+func GetExperimentalConditions(ome *OME, plateIdx, wellIdx int) (map[string]string, error) {
+	plate, err := getPlate(ome, plateIdx)
+	if err != nil {
+		return nil, err
+	}
+	if wellIdx < 0 || wellIdx >= len(plate.Wells) {
+		return nil, fmt.Errorf("well index %d out of range (%d wells in plate %d)", wellIdx, len(plate.Wells), plateIdx)
+	}
+
+	conditions := make(map[string]string)
+	for _, annotation := range linkedMapAnnotations(ome, plate.Wells[wellIdx].AnnotationRefs) {
+		for _, pair := range annotation.Values {
+			conditions[pair.Key] = pair.Value
+		}
+	}
+
+	return conditions, nil
+}
@@ -0,0 +1,171 @@
+package bfmetadata
+
+import "fmt"
+
+// GetMicroscopeType returns the Type of the first microscope described in
+// the OME metadata, e.g. "Inverted" or "Upright".
+func GetMicroscopeType(ome *OME) (string, error) {
+	microscope, err := firstMicroscope(ome)
+	if err != nil {
+		return "", err
+	}
+
+	return microscope.Type, nil
+}
+
+// GetMicroscopeModel returns the Model of the first microscope described in
+// the OME metadata.
+func GetMicroscopeModel(ome *OME) (string, error) {
+	microscope, err := firstMicroscope(ome)
+	if err != nil {
+		return "", err
+	}
+
+	return microscope.Model, nil
+}
+
+// firstMicroscope returns the microscope of the first instrument that has one.
+func firstMicroscope(ome *OME) (*Microscope, error) {
+	if ome == nil {
+		return nil, fmt.Errorf("no OME metadata provided")
+	}
+
+	for _, instrument := range ome.Instruments {
+		if instrument.Microscope != nil {
+			return instrument.Microscope, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no microscope found in instrument metadata")
+}
+
+// getObjectiveForImage resolves the objective referenced by an image's
+// ObjectiveSettings, searching the referenced instrument first.
+func getObjectiveForImage(ome *OME, image *Image) (*Objective, error) {
+	if image.ObjectiveSettings == nil || image.ObjectiveSettings.ID == "" {
+		return nil, fmt.Errorf("image %s has no objective settings", image.ID)
+	}
+
+	id := image.ObjectiveSettings.ID
+	for i := range ome.Instruments {
+		instrument := &ome.Instruments[i]
+		if image.InstrumentRef != nil && image.InstrumentRef.ID != instrument.ID {
+			continue
+		}
+		for j := range instrument.Objectives {
+			if instrument.Objectives[j].ID == id {
+				return &instrument.Objectives[j], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("objective %s referenced by image %s not found", id, image.ID)
+}
+
+// InstrumentNotFoundError is returned when an InstrumentRef points to an
+// instrument that is not declared in the OME metadata.
+type InstrumentNotFoundError struct {
+	ID string
+}
+
+func (e InstrumentNotFoundError) Error() string {
+	return fmt.Sprintf("instrument %s not found", e.ID)
+}
+
+// GetInstrumentForImage follows the InstrumentRef of the image with the
+// given ID and returns the referenced instrument. A dangling reference
+// returns an InstrumentNotFoundError.
+func GetInstrumentForImage(ome *OME, imageID string) (Instrument, error) {
+	image, err := getImageByID(ome, imageID)
+	if err != nil {
+		return Instrument{}, err
+	}
+	if image.InstrumentRef == nil || image.InstrumentRef.ID == "" {
+		return Instrument{}, fmt.Errorf("image %s has no instrument reference", imageID)
+	}
+
+	for _, instrument := range ome.Instruments {
+		if instrument.ID == image.InstrumentRef.ID {
+			return instrument, nil
+		}
+	}
+
+	return Instrument{}, InstrumentNotFoundError{ID: image.InstrumentRef.ID}
+}
+
+// GetEquipmentSerialNumbers returns the serial numbers of all microscopes,
+// light sources, detectors and objectives, keyed by element ID. Microscopes
+// have no ID of their own and are keyed "Microscope:<instrument index>".
+// Equipment without a serial number is omitted.
+func GetEquipmentSerialNumbers(ome *OME) map[string]string {
+	serials := make(map[string]string)
+	if ome == nil {
+		return serials
+	}
+
+	add := func(id, serial string) {
+		if serial != "" {
+			serials[id] = serial
+		}
+	}
+
+	for i, instrument := range ome.Instruments {
+		if instrument.Microscope != nil {
+			add(fmt.Sprintf("Microscope:%d", i), instrument.Microscope.SerialNumber)
+		}
+		for _, source := range instrument.LightSources() {
+			add(source.ID, source.SerialNumber)
+		}
+		for _, detector := range instrument.Detectors {
+			add(detector.ID, detector.SerialNumber)
+		}
+		for _, objective := range instrument.Objectives {
+			add(objective.ID, objective.SerialNumber)
+		}
+	}
+
+	return serials
+}
+
+// LightSources returns the common attributes of every light source of the
+// instrument, regardless of its type.
+func (i Instrument) LightSources() []LightSource {
+	var sources []LightSource
+	for _, l := range i.Lasers {
+		sources = append(sources, l.LightSource)
+	}
+	for _, a := range i.Arcs {
+		sources = append(sources, a.LightSource)
+	}
+	for _, f := range i.Filaments {
+		sources = append(sources, f.LightSource)
+	}
+	for _, led := range i.LightEmittingDiodes {
+		sources = append(sources, led.LightSource)
+	}
+	for _, g := range i.GenericExcitationSources {
+		sources = append(sources, g.LightSource)
+	}
+
+	return sources
+}
+
+// GetFilterPartNumbers returns the manufacturer part number, recorded as the
+// filter Model, of every filter, keyed by filter ID. Filters without a Model
+// are omitted.
+func GetFilterPartNumbers(ome *OME) map[string]string {
+	parts := make(map[string]string)
+	if ome == nil {
+		return parts
+	}
+
+	for _, instrument := range ome.Instruments {
+		for _, filter := range instrument.Filters {
+			if filter.Model != "" {
+				parts[filter.ID] = filter.Model
+			}
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,86 @@
+package bfmetadata
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IntegrityReport summarises whether Bio-Formats could fully read a file.
+type IntegrityReport struct {
+	IsReadable  bool
+	Warnings    []string
+	Errors      []string
+	SeriesCount int
+	ReaderClass string
+}
+
+var (
+	seriesCountPattern = regexp.MustCompile(`^Series count = (\d+)`)
+	readerPattern      = regexp.MustCompile(`reader:\s*(\S+)`)
+)
+
+// GetFileIntegrityCheck is a wrapper around the default Client's GetFileIntegrityCheck.
+func GetFileIntegrityCheck(ctx context.Context, filePath string) (IntegrityReport, error) {
+	return defaultClient.GetFileIntegrityCheck(ctx, filePath)
+}
+
+// GetFileIntegrityCheck runs showinf -nopix -validate against filePath and
+// reports the reader used, the number of series and any warnings or errors
+// printed. A file Bio-Formats fails to read yields a report with IsReadable
+// false; an error is returned only when showinf could not be run at all.
+func (c *Client) GetFileIntegrityCheck(ctx context.Context, filePath string) (IntegrityReport, error) {
+	if err := checkInputFile(filePath); err != nil {
+		return IntegrityReport{}, err
+	}
+
+	stdout, stderr, err := c.runTool(ctx, "showinf", filePath, "-nopix", "-validate")
+	if ctx.Err() != nil {
+		return IntegrityReport{}, ctx.Err()
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return IntegrityReport{}, err
+	}
+
+	report := parseIntegrityOutput(stdout + "\n" + stderr)
+	report.IsReadable = err == nil && len(report.Errors) == 0
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("showinf exited with code %d", exitErr.ExitCode()))
+	}
+
+	return report, nil
+}
+
+// parseIntegrityOutput scans showinf output for reader, series and problem lines.
+func parseIntegrityOutput(output string) IntegrityReport {
+	var report IntegrityReport
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		upper := strings.ToUpper(line)
+
+		switch {
+		case line == "", strings.Contains(upper, "NO VALIDATION ERRORS"):
+		case seriesCountPattern.MatchString(line):
+			count, _ := strconv.Atoi(seriesCountPattern.FindStringSubmatch(line)[1])
+			report.SeriesCount = count
+		case report.ReaderClass == "" && readerPattern.MatchString(line):
+			report.ReaderClass = readerPattern.FindStringSubmatch(line)[1]
+		case strings.Contains(upper, "ERROR") || strings.Contains(line, "Exception"):
+			report.Errors = append(report.Errors, line)
+		case strings.Contains(upper, "WARN"):
+			report.Warnings = append(report.Warnings, line)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,275 @@
+package bfmetadata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// legacyFCNamespace is the namespace URI of the OME-XML 2003-FC schema.
+const legacyFCNamespace = "http://www.openmicroscopy.org/XMLschemas/OME/FC/ome.xsd"
+
+// customAttributesNamespace is the namespace of map annotations converted
+// from 2003-FC custom attributes; the element name is appended.
+const customAttributesNamespace = "openmicroscopy.org/CustomAttributes/"
+
+// legacyAttributeNames maps attribute names of older schemas to their
+// 2016-06 equivalents.
+var legacyAttributeNames = map[string]string{
+	"PixelType":  "Type",
+	"PixelSizeX": "PhysicalSizeX",
+	"PixelSizeY": "PhysicalSizeY",
+	"PixelSizeZ": "PhysicalSizeZ",
+	"ExWave":     "ExcitationWavelength",
+	"EmWave":     "EmissionWavelength",
+}
+
+// Elements of older schemas that have no 2016-06 equivalent and are dropped.
+var legacyDroppedElements = map[string]bool{
+	"ST":                      true,
+	"SemanticTypeDefinitions": true,
+	"AnalysisModuleLibrary":   true,
+	"DefaultPixels":           true,
+	"DisplayOptions":          true,
+	"ChannelComponent":        true,
+}
+
+// ParseLegacyOMEXML decodes OME-XML written against an older schema, from
+// 2003-FC through 2015-01, into the current OME struct. 2003-FC custom
+// attributes are unwrapped: Pixels, Dimensions and channel elements are
+// merged into the image's Pixels and all other custom attributes become map
+// annotations. LogicalChannel and ChannelInfo elements become Pixels
+// channels, CreationDate becomes AcquisitionDate and semantic type
+// definitions are dropped. Documents in the 2016-06 schema are parsed as is.
+func ParseLegacyOMEXML(xmlData string) (*OME, error) {
+	root, err := parseXMLTree(xmlData)
+	if err != nil {
+		return nil, err
+	}
+	if root.Name.Local != "OME" {
+		return nil, fmt.Errorf("root element is %s, not OME", root.Name.Local)
+	}
+
+	switch {
+	case root.Name.Space == omeNamespace2016:
+		return parseXML(xmlData)
+	case root.Name.Space == legacyFCNamespace, strings.HasPrefix(root.Name.Space, omeSchemaPrefix+"OME/"):
+	default:
+		return nil, fmt.Errorf("unsupported OME-XML namespace %q", root.Name.Space)
+	}
+
+	upgrader := &legacyUpgrader{}
+	upgrader.upgradeRoot(root)
+	setNamespace(root, omeNamespace2016)
+
+	return parseXML(serializeXMLTree(root, map[string]string{omeNamespace2016: ""}))
+}
+
+// legacyUpgrader rewrites a legacy element tree into the 2016-06 layout and
+// collects the map annotations created from custom attributes.
+type legacyUpgrader struct {
+	annotations []*xmlNode
+}
+
+func (u *legacyUpgrader) upgradeRoot(root *xmlNode) {
+	var children []*xmlNode
+	var annotations *xmlNode
+	for _, child := range root.Children {
+		switch child.Name.Local {
+		case "Image":
+			u.upgradeImage(child)
+			children = append(children, child)
+		case "CustomAttributes":
+			for _, attr := range child.Children {
+				u.addAnnotation(attr)
+			}
+		case "StructuredAnnotations":
+			annotations = child
+		default:
+			if !legacyDroppedElements[child.Name.Local] {
+				children = append(children, child)
+			}
+		}
+	}
+
+	if len(u.annotations) > 0 {
+		if annotations == nil {
+			annotations = &xmlNode{Name: xml.Name{Local: "StructuredAnnotations"}}
+		}
+		annotations.Children = append(annotations.Children, u.annotations...)
+	}
+	if annotations != nil {
+		children = append(children, annotations)
+	}
+
+	root.Attrs = upgradeAttrs(root.Attrs)
+	root.Children = children
+}
+
+func (u *legacyUpgrader) upgradeImage(image *xmlNode) {
+	var pixels *xmlNode
+	var dimensions []*xmlNode
+	var channels []*xmlNode
+	var children []*xmlNode
+	var refs []*xmlNode
+	var acquisitionDate string
+
+	var visit func(nodes []*xmlNode, inCustomAttributes bool)
+	visit = func(nodes []*xmlNode, inCustomAttributes bool) {
+		for _, child := range nodes {
+			switch child.Name.Local {
+			case "CustomAttributes":
+				visit(child.Children, true)
+			case "Pixels":
+				if pixels == nil {
+					pixels = child
+				}
+			case "Dimensions":
+				dimensions = append(dimensions, child)
+			case "LogicalChannel", "ChannelInfo":
+				channels = append(channels, child)
+			case "CreationDate":
+				acquisitionDate = child.Text
+			default:
+				switch {
+				case legacyDroppedElements[child.Name.Local]:
+				case inCustomAttributes:
+					refs = append(refs, &xmlNode{
+						Name:  xml.Name{Local: "AnnotationRef"},
+						Attrs: []xml.Attr{{Name: xml.Name{Local: "ID"}, Value: u.addAnnotation(child)}},
+					})
+				default:
+					children = append(children, child)
+				}
+			}
+		}
+	}
+	visit(image.Children, false)
+
+	var attrs []xml.Attr
+	for _, attr := range image.Attrs {
+		switch attr.Name.Local {
+		case "CreationDate", "AcquisitionDate":
+			acquisitionDate = attr.Value
+		case "DefaultPixels":
+		default:
+			attrs = append(attrs, attr)
+		}
+	}
+	image.Attrs = upgradeAttrs(attrs)
+
+	if pixels == nil {
+		pixels = &xmlNode{Name: xml.Name{Local: "Pixels"}}
+	}
+	pixels.Attrs = upgradeAttrs(pixels.Attrs)
+	for _, dims := range dimensions {
+		for _, attr := range upgradeAttrs(dims.Attrs) {
+			if strings.HasPrefix(attr.Name.Local, "PhysicalSize") {
+				pixels.Attrs = setAttr(pixels.Attrs, attr.Name.Local, attr.Value)
+			}
+		}
+	}
+
+	var pixelChildren []*xmlNode
+	for i, channel := range channels {
+		channel.Name.Local = "Channel"
+		channel.Attrs = upgradeAttrs(channel.Attrs)
+		if attrValue(channel.Attrs, "ID") == "" {
+			channel.Attrs = setAttr(channel.Attrs, "ID", fmt.Sprintf("Channel:%d", i))
+		}
+		var kept []*xmlNode
+		for _, c := range channel.Children {
+			if !legacyDroppedElements[c.Name.Local] {
+				kept = append(kept, c)
+			}
+		}
+		channel.Children = kept
+		pixelChildren = append(pixelChildren, channel)
+	}
+	pixels.Children = append(pixelChildren, pixels.Children...)
+
+	image.Children = nil
+	if acquisitionDate != "" {
+		image.Children = append(image.Children, &xmlNode{Name: xml.Name{Local: "AcquisitionDate"}, Text: acquisitionDate})
+	}
+	image.Children = append(image.Children, children...)
+	image.Children = append(image.Children, pixels)
+	image.Children = append(image.Children, refs...)
+}
+
+// addAnnotation converts a custom attribute element into a map annotation
+// holding its attributes and returns the annotation ID.
+func (u *legacyUpgrader) addAnnotation(node *xmlNode) string {
+	id := fmt.Sprintf("Annotation:CustomAttributes:%d", len(u.annotations))
+
+	value := &xmlNode{Name: xml.Name{Local: "Value"}}
+	for _, attr := range node.Attrs {
+		if isNamespaceDecl(attr) {
+			continue
+		}
+		value.Children = append(value.Children, &xmlNode{
+			Name:  xml.Name{Local: "M"},
+			Attrs: []xml.Attr{{Name: xml.Name{Local: "K"}, Value: attr.Name.Local}},
+			Text:  attr.Value,
+		})
+	}
+
+	u.annotations = append(u.annotations, &xmlNode{
+		Name: xml.Name{Local: "MapAnnotation"},
+		Attrs: []xml.Attr{
+			{Name: xml.Name{Local: "ID"}, Value: id},
+			{Name: xml.Name{Local: "Namespace"}, Value: customAttributesNamespace + node.Name.Local},
+		},
+		Children: []*xmlNode{value},
+	})
+
+	return id
+}
+
+// upgradeAttrs renames legacy attributes and drops namespace-qualified ones,
+// such as declarations and xsi:schemaLocation pointing at the old schema.
+func upgradeAttrs(attrs []xml.Attr) []xml.Attr {
+	var upgraded []xml.Attr
+	for _, attr := range attrs {
+		if attr.Name.Space != "" || isNamespaceDecl(attr) {
+			continue
+		}
+		if name, ok := legacyAttributeNames[attr.Name.Local]; ok {
+			attr.Name.Local = name
+		}
+		upgraded = append(upgraded, attr)
+	}
+
+	return upgraded
+}
+
+// setAttr sets the value of an unqualified attribute, adding it if missing.
+func setAttr(attrs []xml.Attr, name, value string) []xml.Attr {
+	for i := range attrs {
+		if attrs[i].Name.Space == "" && attrs[i].Name.Local == name {
+			attrs[i].Value = value
+			return attrs
+		}
+	}
+
+	return append(attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
+
+// attrValue returns the value of an unqualified attribute.
+func attrValue(attrs []xml.Attr, name string) string {
+	for _, attr := range attrs {
+		if attr.Name.Space == "" && attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// setNamespace moves every element of the tree into namespace uri.
+func setNamespace(node *xmlNode, uri string) {
+	node.Name.Space = uri
+	for _, child := range node.Children {
+		setNamespace(child, uri)
+	}
+}
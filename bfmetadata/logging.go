@@ -0,0 +1,104 @@
+package bfmetadata
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Logger receives diagnostic messages from the package.
+type Logger interface {
+	Log(level, msg string)
+}
+
+// Log levels passed to Logger.Log.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+var (
+	loggerMu      sync.RWMutex
+	packageLogger Logger
+)
+
+// SetLogger configures the logger used by package-level functions. A nil
+// logger, the default, discards all messages.
+func SetLogger(l Logger) {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	packageLogger = l
+}
+
+// logf formats and forwards a message to the configured logger, if any.
+func logf(level, format string, args ...interface{}) {
+	loggerMu.RLock()
+	l := packageLogger
+	loggerMu.RUnlock()
+
+	if l != nil {
+		l.Log(level, fmt.Sprintf(format, args...))
+	}
+}
+
+// hasPackageLogger reports whether a package-level logger is configured.
+func hasPackageLogger() bool {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+
+	return packageLogger != nil
+}
+
+// toolLogLevel returns the level a line of tool stderr is logged at: error
+// for lines tagged [ERROR] by the Bio-Formats logger, debug for the rest.
+func toolLogLevel(line string) string {
+	if strings.HasPrefix(line, "[ERROR]") {
+		return LogLevelError
+	}
+
+	return LogLevelDebug
+}
+
+// lineWriter splits written output into lines, treating the carriage
+// returns used by progress output as line breaks, and passes each non-empty
+// line to onLine. It is safe for concurrent use by stdout and stderr copies.
+type lineWriter struct {
+	mu     sync.Mutex
+	buf    []byte
+	onLine func(string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush passes any unterminated last line to onLine.
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.emit(w.buf)
+	w.buf = nil
+}
+
+func (w *lineWriter) emit(line []byte) {
+	if text := strings.TrimSpace(string(line)); text != "" {
+		w.onLine(text)
+	}
+}
@@ -2,47 +2,21 @@ package bfmetadata
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	_ "embed"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 )
 
-type OME struct {
-	XMLName xml.Name `xml:"OME"`
-	Image   Image    `xml:"Image"`
-}
-
-type Image struct {
-	ID              string `xml:"ID,attr"`
-	Name            string `xml:"Name,attr"`
-	AcquisitionDate string `xml:"AcquisitionDate"`
-	Pixels          Pixels `xml:"Pixels"`
-}
-
-type Pixels struct {
-	BigEndian         string `xml:"BigEndian,attr"`
-	DimensionOrder    string `xml:"DimensionOrder,attr"`
-	ID                string `xml:"ID,attr"`
-	Interleaved       string `xml:"Interleaved,attr"`
-	PhysicalSizeX     string `xml:"PhysicalSizeX,attr"`
-	PhysicalSizeXUnit string `xml:"PhysicalSizeXUnit,attr"`
-	PhysicalSizeY     string `xml:"PhysicalSizeY,attr"`
-	PhysicalSizeYUnit string `xml:"PhysicalSizeYUnit,attr"`
-	PhysicalSizeZ     string `xml:"PhysicalSizeZ,attr"`
-	PhysicalSizeZUnit string `xml:"PhysicalSizeZUnit,attr"`
-	SignificantBits   int    `xml:"SignificantBits,attr"`
-	SizeC             int    `xml:"SizeC,attr"`
-	SizeT             int    `xml:"SizeT,attr"`
-	SizeX             int    `xml:"SizeX,attr"`
-	SizeY             int    `xml:"SizeY,attr"`
-	SizeZ             int    `xml:"SizeZ,attr"`
-	Type              string `xml:"Type,attr"`
-}
-
 // Embed bfconvert.bat
 //
 //go:embed bftools/bfconvert.bat
@@ -68,142 +42,553 @@ var configBat []byte
 //go:embed bftools/showinf.bat
 var showinfBat []byte
 
-// PrintHelp executes the bfconvert.bat with the --help flag and returns the output.
-func PrintHelp() (string, error) {
-	tempDir, err := prepareFiles()
-	if err != nil {
-		return "", err
+// Embed bfconvert
+//
+//go:embed bftools/bfconvert
+var bfconvertSh []byte
+
+// Embed showinf
+//
+//go:embed bftools/showinf
+var showinfSh []byte
+
+// Embed bf.sh
+//
+//go:embed bftools/bf.sh
+var bfSh []byte
+
+// Embed config.sh
+//
+//go:embed bftools/config.sh
+var configSh []byte
+
+// windowsScripts and unixScripts map the embedded launcher scripts for each
+// platform to their contents.
+var (
+	windowsScripts = map[string][]byte{
+		"bfconvert.bat": bfconvertBat,
+		"bf.bat":        bfBat,
+		"config.bat":    configBat,
+		"showinf.bat":   showinfBat,
+	}
+	unixScripts = map[string][]byte{
+		"bfconvert": bfconvertSh,
+		"bf.sh":     bfSh,
+		"config.sh": configSh,
+		"showinf":   showinfSh,
 	}
+)
 
-	batFile := filepath.Join(tempDir, "bfconvert.bat")
+// toolFiles maps all embedded Bio-Formats tool files to their contents.
+func toolFiles() map[string][]byte {
+	files := map[string][]byte{"bioformats_package.jar": bioformatsJar}
+	for name, data := range windowsScripts {
+		files[name] = data
+	}
+	for name, data := range unixScripts {
+		files[name] = data
+	}
 
-	cmd := exec.Command("cmd", "/C", batFile, "--help")
-	cmd.Env = append(os.Environ(), fmt.Sprintf("BF_DIR=%s", tempDir))
+	return files
+}
 
-	var out bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
+// platformScripts returns the launcher scripts to extract on goos.
+func platformScripts(goos string) map[string][]byte {
+	if goos == "windows" {
+		return windowsScripts
+	}
+
+	return unixScripts
+}
+
+// PrintHelp is a wrapper around the default Client's PrintHelp.
+func PrintHelp(ctx context.Context) (string, error) {
+	return defaultClient.PrintHelp(ctx)
+}
 
-	err = cmd.Run()
+// PrintHelp executes bfconvert with the --help flag and returns the output.
+func (c *Client) PrintHelp(ctx context.Context) (string, error) {
+	out, _, err := c.runTool(ctx, "bfconvert", "--help")
 	if err != nil {
-		return out.String(), fmt.Errorf("error executing bfconvert.bat --help: %w, raw stderr: %s", err, stderr.String())
+		return out, err
 	}
 
-	return out.String(), nil
+	return out, nil
+}
+
+// GetOmexmlMetadata is a wrapper around the default Client's GetOmexmlMetadata.
+func GetOmexmlMetadata(ctx context.Context, filePath string, series int) (string, error) {
+	return defaultClient.GetOmexmlMetadata(ctx, filePath, series)
 }
 
-// GetOmexmlMetadata extracts and cleans OME-XML metadata from a given file using showinf.bat
-func GetOmexmlMetadata(filePath string) (string, error) {
-	tempDir, err := prepareFiles()
+// AllSeries selects every series of a file in GetOmexmlMetadata.
+const AllSeries = -1
+
+// GetOmexmlMetadata extracts and cleans OME-XML metadata from a given file
+// using showinf. A non-negative series is passed to showinf as -series so
+// that only that series is read; AllSeries reads the whole file. The Java
+// process is killed when ctx is done.
+func (c *Client) GetOmexmlMetadata(ctx context.Context, filePath string, series int) (string, error) {
+	if err := checkInputFile(filePath); err != nil {
+		return "", err
+	}
+	if series < AllSeries {
+		return "", fmt.Errorf("invalid series index %d", series)
+	}
+
+	// Execute showinf with -nopix to extract metadata
+	args := []string{filePath, "-omexml-only", "-nopix"}
+	if series != AllSeries {
+		args = append(args, "-series", strconv.Itoa(series))
+	}
+	output, stderr, err := c.runTool(ctx, "showinf", args...)
 	if err != nil {
 		return "", err
 	}
 
-	batFile := filepath.Join(tempDir, "showinf.bat")
+	// Prefer XML printed to stdout, falling back to stderr for log-level dependent output
+	if xmlData, err := ExtractOMEXMLFromOutput(output); err == nil {
+		return xmlData, nil
+	}
+	if xmlData, err := ExtractOMEXMLFromOutput(stderr); err == nil {
+		return xmlData, nil
+	}
+
+	return "", fmt.Errorf("no XML content found in output: %s", stderr)
+}
+
+// ExtractOMEXMLFromOutput returns the OME-XML document embedded in captured
+// showinf output, dropping any log lines printed before or after it.
+func ExtractOMEXMLFromOutput(combined string) (string, error) {
+	xmlIndex := strings.Index(combined, "<?xml")
+	if xmlIndex == -1 {
+		return "", fmt.Errorf("no XML content found in output")
+	}
+
+	xmlData := combined[xmlIndex:]
+	if end := strings.LastIndex(xmlData, "OME>"); end != -1 {
+		xmlData = xmlData[:end+len("OME>")]
+	}
+
+	return xmlData, nil
+}
+
+// runTool executes one of the extracted Bio-Formats tools, e.g. "showinf",
+// using the launcher script for the current platform and returns its stdout
+// and stderr. A failed run is reported as a ToolExecutionError.
+func (c *Client) runTool(ctx context.Context, tool string, args ...string) (string, string, error) {
+	return c.run(ctx, tool, nil, args, nil)
+}
+
+// runProgram executes a Bio-Formats command line class that has no launcher
+// script of its own through the generic bf launcher.
+func (c *Client) runProgram(ctx context.Context, class string, args ...string) (string, string, error) {
+	return c.run(ctx, "bf", []string{"BF_PROG=" + class}, args, nil)
+}
+
+// run executes the launcher script for tool with env added to the environment.
+// When onLine is set, every line the tool writes is passed to it as soon as
+// it is printed. Otherwise, if a logger is configured, stderr is forwarded to
+// it line by line.
+func (c *Client) run(ctx context.Context, tool string, env, args []string, onLine func(string)) (string, string, error) {
+	tempDir, err := c.prepare()
+	if err != nil {
+		return "", "", err
+	}
+
+	name, cmdArgs := toolCommand(runtime.GOOS, tempDir, tool)
+	c.logf(LogLevelDebug, "running %s %s", tool, strings.Join(args, " "))
 
-	// Prepare the command to execute showinf.bat with -nopix to extract metadata
-	cmd := exec.Command("cmd", "/C", batFile, filePath, "-omexml-only", "-nopix")
+	cmd := exec.CommandContext(ctx, name, append(cmdArgs, args...)...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("BF_DIR=%s", tempDir))
+	if heap := c.jvmMaxMemory(); heap != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("BF_MAX_MEM=%s", heap))
+	}
+	cmd.Env = append(cmd.Env, env...)
 
 	var out bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &stderr
+	switch {
+	case onLine != nil:
+		lines := &lineWriter{onLine: onLine}
+		defer lines.Flush()
+		cmd.Stdout = io.MultiWriter(&out, lines)
+		cmd.Stderr = io.MultiWriter(&stderr, lines)
+	case c.hasLogger():
+		lines := &lineWriter{onLine: func(line string) {
+			c.logf(toolLogLevel(line), "%s: %s", tool, line)
+		}}
+		defer lines.Flush()
+		cmd.Stderr = io.MultiWriter(&stderr, lines)
+	}
 
-	cmd.Env = append(os.Environ(), fmt.Sprintf("BF_DIR=%s", tempDir))
+	if err := cmd.Run(); err != nil {
+		return out.String(), stderr.String(), newToolExecutionError(tool, stderr.String(), err)
+	}
+
+	return out.String(), stderr.String(), nil
+}
 
-	// Execute the command
-	err = cmd.Run()
+// toolCommand returns the program and arguments launching tool from dir on
+// goos: cmd /C with the .bat script on Windows, the executable shell script
+// itself elsewhere so that its bash shebang is honoured. Unix helper scripts
+// such as bf carry a .sh extension.
+func toolCommand(goos, dir, tool string) (string, []string) {
+	if goos == "windows" {
+		return "cmd", []string{"/C", filepath.Join(dir, tool+".bat")}
+	}
+
+	script := tool
+	if _, ok := unixScripts[script]; !ok {
+		script += ".sh"
+	}
+
+	return filepath.Join(dir, script), nil
+}
+
+// toolFileSystem is the file system the embedded tools are extracted to.
+var toolFileSystem fileSystem = osFileSystem{}
+
+// toolDirState guards the extraction of the embedded tools to one directory.
+type toolDirState struct {
+	mu       sync.Mutex
+	prepared bool
+}
+
+// toolDirs holds the extraction state of every tool directory used by the
+// process, keyed by absolute path.
+var (
+	toolDirsMu sync.Mutex
+	toolDirs   = make(map[string]*toolDirState)
+)
+
+// prepare extracts the embedded tools to the client's temp directory. The
+// tools are extracted once per directory and process, however many Clients
+// use it; concurrent callers block until extraction has finished. A failed
+// extraction is retried by the next caller.
+func (c *Client) prepare() (string, error) {
+	dir := c.tempDir
+	if dir == "" {
+		dir = defaultToolDir()
+	}
+	dir, err := filepath.Abs(dir)
 	if err != nil {
-		return "", fmt.Errorf("error executing showinf.bat to get metadata: %w, stderr: %s", err, stderr.String())
+		return "", fmt.Errorf("error resolving tool directory %s: %w", dir, err)
 	}
 
-	// Capture the command output and clean it to extract the XML content
-	output := out.String()
-	xmlIndex := strings.Index(output, "<?xml")
-	if xmlIndex != -1 {
-		return output[xmlIndex:], nil
+	toolDirsMu.Lock()
+	state, ok := toolDirs[dir]
+	if !ok {
+		state = &toolDirState{}
+		toolDirs[dir] = state
 	}
+	toolDirsMu.Unlock()
 
-	return "", fmt.Errorf("no XML content found in output: %s", stderr.String())
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.prepared {
+		return dir, nil
+	}
+
+	if _, err := prepareFiles(toolFileSystem, dir); err != nil {
+		return "", err
+	}
+	state.prepared = true
+	c.logf(LogLevelDebug, "Bio-Formats tools extracted to %s", dir)
+
+	return dir, nil
 }
 
-// prepareFiles ensures the necessary files are present in a designated temp directory.
-func prepareFiles() (string, error) {
-	tempDir := filepath.Join(os.TempDir(), "bioformats")
-	if _, err := os.Stat(tempDir); os.IsNotExist(err) {
-		err = os.MkdirAll(tempDir, 0755)
+// prepareFiles ensures the embedded jar and platform scripts are present in
+// tempDir on fsys and returns it. An empty tempDir selects defaultToolDir.
+func prepareFiles(fsys fileSystem, tempDir string) (string, error) {
+	if tempDir == "" {
+		tempDir = defaultToolDir()
+	}
+	if _, err := fsys.Stat(tempDir); os.IsNotExist(err) {
+		err = fsys.MkdirAll(tempDir, 0755)
 		if err != nil {
-			return "", fmt.Errorf("error creating temp directory: %w", err)
+			return "", fmt.Errorf("error creating temp directory %s: %w", tempDir, err)
 		}
 	}
 
-	files := map[string][]byte{
-		"bfconvert.bat":          bfconvertBat,
-		"bioformats_package.jar": bioformatsJar,
-		"bf.bat":                 bfBat,
-		"config.bat":             configBat,
-		"showinf.bat":            showinfBat,
+	if err := writeToolFile(fsys, tempDir, "bioformats_package.jar", bioformatsJar, 0644); err != nil {
+		return "", err
 	}
 
-	for filename, data := range files {
-		path := filepath.Join(tempDir, filename)
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			err := os.WriteFile(path, data, 0644)
-			if err != nil {
-				return "", fmt.Errorf("error writing %s to temp file: %w", filename, err)
-			}
+	for filename, data := range platformScripts(runtime.GOOS) {
+		if err := writeToolFile(fsys, tempDir, filename, data, 0755); err != nil {
+			return "", err
 		}
 	}
 
 	return tempDir, nil
 }
 
-func GetEssentialMetadata(imageFilePath string) (map[string]interface{}, error) {
-	// Simulate retrieving OME-XML metadata for the specified image file
-	metadataxml, err := GetOmexmlMetadata(imageFilePath)
+// embeddedHashes holds the SHA-256 digest of every embedded tool file.
+var embeddedHashes = func() map[string][32]byte {
+	hashes := make(map[string][32]byte)
+	for name, data := range toolFiles() {
+		hashes[name] = sha256.Sum256(data)
+	}
+
+	return hashes
+}()
+
+// writeToolFile writes an embedded tool file with the given permissions
+// unless an identical copy already exists. A truncated, corrupted or
+// outdated file on disk is replaced, as is one with other permissions on
+// systems with Unix file modes.
+func writeToolFile(fsys fileSystem, dir, filename string, data []byte, perm os.FileMode) error {
+	path := filepath.Join(dir, filename)
+	if fileHasHash(fsys, path, embeddedHashes[filename]) {
+		info, err := fsys.Stat(path)
+		if err == nil && (runtime.GOOS == "windows" || info.Mode().Perm() == perm) {
+			return nil
+		}
+	}
+
+	if err := fsys.WriteFile(path, data, perm); err != nil {
+		return fmt.Errorf("error extracting %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+// fileHasHash reports whether the file at path exists and has the SHA-256
+// digest sum.
+func fileHasHash(fsys fileSystem, path string, sum [32]byte) bool {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return sha256.Sum256(data) == sum
+}
+
+// EssentialMetadata holds the metadata of one series most callers need.
+type EssentialMetadata struct {
+	Series            int
+	Name              string
+	AcquisitionDate   string
+	DimensionOrder    string
+	PhysicalSizeX     float64
+	PhysicalSizeXUnit string
+	PhysicalSizeY     float64
+	PhysicalSizeYUnit string
+	PhysicalSizeZ     float64
+	PhysicalSizeZUnit string
+	Size              ImageSize
+	PixelBitDepth     int
+	Channels          []Channel
+
+	// ObjectiveNA and ObjectiveMagnification describe the objective linked
+	// through the image's ObjectiveSettings; they are 0 when none is linked.
+	ObjectiveNA            float64
+	ObjectiveMagnification float64
+
+	// ExperimenterEmail and Institution describe the experimenter referenced
+	// by the image's ExperimenterRef; they are empty when none is linked.
+	ExperimenterEmail string
+	Institution       string
+}
+
+// ImageSize holds the pixel dimensions of a series.
+type ImageSize struct {
+	C, T, X, Y, Z int
+}
+
+// GetEssentialMetadata is a wrapper around the default Client's GetEssentialMetadata.
+func GetEssentialMetadata(ctx context.Context, imageFilePath string) (*EssentialMetadata, error) {
+	return defaultClient.GetEssentialMetadata(ctx, imageFilePath)
+}
+
+// GetEssentialMetadata returns the essential metadata of the first series
+// of an image file.
+func (c *Client) GetEssentialMetadata(ctx context.Context, imageFilePath string) (*EssentialMetadata, error) {
+	return c.GetSeriesMetadata(ctx, imageFilePath, 0)
+}
+
+// GetEssentialMetadataMap is a wrapper around the default Client's GetEssentialMetadataMap.
+func GetEssentialMetadataMap(ctx context.Context, imageFilePath string) (map[string]interface{}, error) {
+	return defaultClient.GetEssentialMetadataMap(ctx, imageFilePath)
+}
+
+// GetEssentialMetadataMap returns the essential metadata of the first series
+// as nested maps, as GetEssentialMetadata did before it returned a struct.
+func (c *Client) GetEssentialMetadataMap(ctx context.Context, imageFilePath string) (map[string]interface{}, error) {
+	metadata, err := c.getParsedMetadata(ctx, imageFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := getImage(metadata, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return essentialMetadataMap(0, image), nil
+}
+
+// GetSeriesMetadata is a wrapper around the default Client's GetSeriesMetadata.
+func GetSeriesMetadata(ctx context.Context, imageFilePath string, seriesIdx int) (*EssentialMetadata, error) {
+	return defaultClient.GetSeriesMetadata(ctx, imageFilePath, seriesIdx)
+}
+
+// GetSeriesMetadata returns the essential metadata of one series of an
+// image file, selected by its zero-based index.
+func (c *Client) GetSeriesMetadata(ctx context.Context, imageFilePath string, seriesIdx int) (*EssentialMetadata, error) {
+	metadata, err := c.getParsedMetadata(ctx, imageFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return essentialMetadata(metadata, seriesIdx)
+}
+
+// GetAllSeriesMetadata is a wrapper around the default Client's GetAllSeriesMetadata.
+func GetAllSeriesMetadata(ctx context.Context, imageFilePath string) ([]*EssentialMetadata, error) {
+	return defaultClient.GetAllSeriesMetadata(ctx, imageFilePath)
+}
+
+// GetAllSeriesMetadata returns the essential metadata of every series of an
+// image file, indexed by series.
+func (c *Client) GetAllSeriesMetadata(ctx context.Context, imageFilePath string) ([]*EssentialMetadata, error) {
+	metadata, err := c.getParsedMetadata(ctx, imageFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]*EssentialMetadata, len(metadata.Images))
+	for i := range metadata.Images {
+		all[i], err = essentialMetadata(metadata, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return all, nil
+}
+
+// getParsedMetadata retrieves and parses the OME-XML metadata of an image file.
+func (c *Client) getParsedMetadata(ctx context.Context, imageFilePath string) (*OME, error) {
+	metadataxml, err := c.GetOmexmlMetadata(ctx, imageFilePath, AllSeries)
 	if err != nil {
 		return nil, err
 	}
 
-	metadata, err := parseXML(metadataxml)
+	return parseOMEXML(strings.NewReader(metadataxml), c.logf)
+}
+
+// essentialMetadata collects the essential metadata of one series.
+func essentialMetadata(ome *OME, seriesIdx int) (*EssentialMetadata, error) {
+	image, err := getImage(ome, seriesIdx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Organize data into a format suitable for YAML
-	essentialMetadata := map[string]interface{}{
+	size, err := getPhysicalSize(ome, seriesIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &EssentialMetadata{
+		Series:            seriesIdx,
+		Name:              image.Name,
+		AcquisitionDate:   image.AcquisitionDateRaw,
+		DimensionOrder:    image.Pixels.DimensionOrder,
+		PhysicalSizeX:     size.X,
+		PhysicalSizeXUnit: size.XUnit,
+		PhysicalSizeY:     size.Y,
+		PhysicalSizeYUnit: size.YUnit,
+		PhysicalSizeZ:     size.Z,
+		PhysicalSizeZUnit: size.ZUnit,
+		Size: ImageSize{
+			C: image.Pixels.SizeC,
+			T: image.Pixels.SizeT,
+			X: image.Pixels.SizeX,
+			Y: image.Pixels.SizeY,
+			Z: image.Pixels.SizeZ,
+		},
+		PixelBitDepth: image.Pixels.SignificantBits,
+		Channels:      image.Pixels.Channels,
+	}
+
+	if objective, err := getObjectiveForImage(ome, image); err == nil {
+		metadata.ObjectiveNA = objective.LensNA
+		metadata.ObjectiveMagnification = objective.NominalMagnification
+	}
+
+	if experimenter := getImageExperimenter(ome, image); experimenter != nil {
+		metadata.ExperimenterEmail = experimenter.Email
+		metadata.Institution = experimenter.Institution
+	}
+
+	return metadata, nil
+}
+
+// essentialMetadataMap organizes the metadata of one series into a format suitable for YAML.
+func essentialMetadataMap(seriesIdx int, image *Image) map[string]interface{} {
+	return map[string]interface{}{
 		"Essential_metadata": map[string]interface{}{
-			"AcquisitionDate": metadata.Image.AcquisitionDate,
-			"DimensionOrder":  metadata.Image.Pixels.DimensionOrder,
+			"Series":          seriesIdx,
+			"Name":            image.Name,
+			"AcquisitionDate": image.AcquisitionDateRaw,
+			"DimensionOrder":  image.Pixels.DimensionOrder,
 			"PhysicalSize": map[string]interface{}{
-				"X": metadata.Image.Pixels.PhysicalSizeX + " " + metadata.Image.Pixels.PhysicalSizeXUnit,
-				"Y": metadata.Image.Pixels.PhysicalSizeY + " " + metadata.Image.Pixels.PhysicalSizeYUnit,
-				"Z": metadata.Image.Pixels.PhysicalSizeZ + " " + metadata.Image.Pixels.PhysicalSizeZUnit,
+				"X": image.Pixels.PhysicalSizeXRaw + " " + image.Pixels.PhysicalSizeXUnit,
+				"Y": image.Pixels.PhysicalSizeYRaw + " " + image.Pixels.PhysicalSizeYUnit,
+				"Z": image.Pixels.PhysicalSizeZRaw + " " + image.Pixels.PhysicalSizeZUnit,
 			},
 			"Size": map[string]interface{}{
-				"C": metadata.Image.Pixels.SizeC,
-				"T": metadata.Image.Pixels.SizeT,
-				"X": metadata.Image.Pixels.SizeX,
-				"Y": metadata.Image.Pixels.SizeY,
-				"Z": metadata.Image.Pixels.SizeZ,
+				"C": image.Pixels.SizeC,
+				"T": image.Pixels.SizeT,
+				"X": image.Pixels.SizeX,
+				"Y": image.Pixels.SizeY,
+				"Z": image.Pixels.SizeZ,
 			},
-			"PixelBitDepth": metadata.Image.Pixels.SignificantBits,
+			"PixelBitDepth": image.Pixels.SignificantBits,
 		},
 	}
-
-	return essentialMetadata, nil
 }
 
 func parseXML(xmlData string) (*OME, error) {
+	return ParseOMEXMLFromReader(strings.NewReader(xmlData))
+}
+
+// ParseOMEXMLFromReader decodes OME-XML read from r, for example an opened
+// companion file, an HTTP response body or an in-memory buffer. Elements are
+// matched by local name, so documents using a namespace prefix such as
+// <ome:OME xmlns:ome="..."> decode the same as ones using the default
+// namespace. Documents declaring an encoding other than UTF-8 are
+// transcoded. Decoding failures are reported as an XMLParseError. A physical
+// size that is not a number is left at 0 and logged as a warning.
+func ParseOMEXMLFromReader(r io.Reader) (*OME, error) {
+	return parseOMEXML(r, logf)
+}
+
+// parseOMEXML decodes OME-XML read from r, passing warnings about values
+// that could not be parsed to warnf.
+func parseOMEXML(r io.Reader, warnf func(level, format string, args ...interface{})) (*OME, error) {
 	var ome OME
+	var read bytes.Buffer
 
-	reader := strings.NewReader(xmlData)
-	decoder := xml.NewDecoder(reader)
+	decoder := xml.NewDecoder(io.TeeReader(r, &read))
 	decoder.DefaultSpace = ""
+	decoder.CharsetReader = charsetReader
 
 	if err := decoder.Decode(&ome); err != nil {
-		return nil, err
+		return nil, newXMLParseError(read.Bytes(), decoder.InputOffset(), err)
+	}
+
+	for i := range ome.Images {
+		pixels := &ome.Images[i].Pixels
+		for _, err := range pixels.sizeErrors {
+			warnf(LogLevelWarn, "pixels %s: %v; using 0", pixels.ID, err)
+		}
+		pixels.sizeErrors = nil
 	}
 
 	return &ome, nil
@@ -0,0 +1,150 @@
+package bfmetadata
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseOMEXMLFromReaderNamespacePrefix(t *testing.T) {
+	ome, err := ParseOMEXMLFromReader(strings.NewReader(readFixture(t, "prefixed.ome.xml")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ome.Images) != 1 {
+		t.Fatalf("got %d images, want 1", len(ome.Images))
+	}
+
+	pixels := ome.Images[0].Pixels
+	if pixels.SizeX == 0 {
+		t.Error("Pixels.SizeX is 0")
+	}
+	if pixels.SizeX != 640 || pixels.SizeY != 480 || pixels.SizeZ != 5 {
+		t.Errorf("got size %dx%dx%d, want 640x480x5", pixels.SizeX, pixels.SizeY, pixels.SizeZ)
+	}
+	if pixels.PhysicalSizeX != 0.1625 {
+		t.Errorf("PhysicalSizeX = %g, want 0.1625", pixels.PhysicalSizeX)
+	}
+	if len(pixels.Channels) != 1 || pixels.Channels[0].Name != "GFP" {
+		t.Errorf("channels = %+v", pixels.Channels)
+	}
+}
+
+// countingFileSystem counts the files written through it.
+type countingFileSystem struct {
+	fileSystem
+
+	mu     sync.Mutex
+	writes map[string]int
+}
+
+func (fs *countingFileSystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	fs.mu.Lock()
+	fs.writes[filepath.Base(path)]++
+	fs.mu.Unlock()
+
+	return fs.fileSystem.WriteFile(path, data, perm)
+}
+
+// useFileSystem replaces toolFileSystem for the duration of the test.
+func useFileSystem(t *testing.T, fsys fileSystem) {
+	t.Helper()
+
+	saved := toolFileSystem
+	toolFileSystem = fsys
+	t.Cleanup(func() { toolFileSystem = saved })
+}
+
+func TestPrepareExtractsOncePerDirectory(t *testing.T) {
+	fsys := &countingFileSystem{fileSystem: osFileSystem{}, writes: make(map[string]int)}
+	useFileSystem(t, fsys)
+
+	dir := t.TempDir()
+	clients := []*Client{
+		NewClient(WithTempDir(dir)),
+		NewClient(WithTempDir(dir + string(filepath.Separator))),
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			got, err := c.prepare()
+			if err == nil && got != dir {
+				t.Errorf("prepare returned %s, want %s", got, dir)
+			}
+			errs <- err
+		}(clients[i%len(clients)])
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := len(platformScripts(runtime.GOOS)) + 1
+	if len(fsys.writes) != want {
+		t.Errorf("wrote %d distinct files, want %d: %v", len(fsys.writes), want, fsys.writes)
+	}
+	for name, n := range fsys.writes {
+		if n != 1 {
+			t.Errorf("%s written %d times, want 1", name, n)
+		}
+	}
+}
+
+// recordingLogger collects the messages logged to it.
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Log(level, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, level+": "+msg)
+}
+
+func TestParseOMEXMLInvalidPhysicalSize(t *testing.T) {
+	const doc = `<OME xmlns="http://www.openmicroscopy.org/Schemas/OME/2016-06">
+  <Image ID="Image:0"><Pixels ID="Pixels:0" SizeX="8" SizeY="8" SizeZ="1" SizeC="1" SizeT="1" PhysicalSizeX="n/a" PhysicalSizeY="0.2"/></Image>
+  <Image ID="Image:1"><Pixels ID="Pixels:1" SizeX="4" SizeY="4" SizeZ="1" SizeC="1" SizeT="1" PhysicalSizeX="0.4"/></Image>
+</OME>`
+
+	packageLogger := &recordingLogger{}
+	SetLogger(packageLogger)
+	t.Cleanup(func() { SetLogger(nil) })
+
+	ome, err := ParseOMEXMLFromReader(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := ome.Images[0].Pixels
+	if p.PhysicalSizeX != 0 || p.PhysicalSizeXRaw != "n/a" || p.PhysicalSizeY != 0.2 {
+		t.Errorf("got X=%g (raw %q), Y=%g", p.PhysicalSizeX, p.PhysicalSizeXRaw, p.PhysicalSizeY)
+	}
+	if got := ome.Images[1].Pixels.PhysicalSizeX; got != 0.4 {
+		t.Errorf("second image PhysicalSizeX = %g, want 0.4", got)
+	}
+	if len(packageLogger.messages) != 1 || !strings.HasPrefix(packageLogger.messages[0], "warn: pixels Pixels:0:") {
+		t.Errorf("package logger got %v", packageLogger.messages)
+	}
+
+	clientLogger := &recordingLogger{}
+	c := NewClient(WithLogger(clientLogger))
+	if _, err := parseOMEXML(strings.NewReader(doc), c.logf); err != nil {
+		t.Fatal(err)
+	}
+	if len(clientLogger.messages) != 1 || len(packageLogger.messages) != 1 {
+		t.Errorf("client logger got %v, package logger got %v", clientLogger.messages, packageLogger.messages)
+	}
+}
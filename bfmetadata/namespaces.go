@@ -0,0 +1,142 @@
+package bfmetadata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GetOMEXMLNamespaces returns the namespace declarations on the root element
+// as a prefix to URI map. The default namespace is stored under "".
+func GetOMEXMLNamespaces(xmlData string) (map[string]string, error) {
+	root, err := rootElement(xmlData)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make(map[string]string)
+	for _, attr := range root.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			namespaces[attr.Name.Local] = attr.Value
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			namespaces[""] = attr.Value
+		}
+	}
+
+	return namespaces, nil
+}
+
+// OME-XML format generations reported by GetOMEXMLFormatGeneration.
+const (
+	OMEXMLGenerationCurrent = "current"
+	OMEXMLGenerationLegacy  = "legacy"
+	OMEXMLGenerationUnknown = "unknown"
+)
+
+// GetOMEXMLFormatGeneration classifies a document by the OME schema
+// namespace of its root element: "current" for 2016-06, "legacy" for older
+// releases such as 2013-06 and the 2003-FC schema, and "unknown" for
+// anything else. A deprecation warning is logged for legacy documents.
+func GetOMEXMLFormatGeneration(xmlData string) (string, error) {
+	root, err := rootElement(xmlData)
+	if err != nil {
+		return "", err
+	}
+
+	namespaces, err := GetOMEXMLNamespaces(xmlData)
+	if err != nil {
+		return "", err
+	}
+	uri := namespaces[root.Name.Space]
+
+	if uri == omeNamespace2016 {
+		return OMEXMLGenerationCurrent, nil
+	}
+
+	version, ok := strings.CutPrefix(uri, omeSchemaPrefix+"OME/")
+	if uri == legacyFCNamespace {
+		version, ok = "2003-FC", true
+	}
+	if !ok || version == "" || version > "2016-06" {
+		return OMEXMLGenerationUnknown, nil
+	}
+
+	logf(LogLevelWarn, "OME-XML uses the deprecated %s schema; convert it to 2016-06 with a current Bio-Formats release", version)
+	return OMEXMLGenerationLegacy, nil
+}
+
+// rootElement returns the raw start token of the document's root element
+// without decoding the rest of the document.
+func rootElement(xmlData string) (xml.StartElement, error) {
+	decoder := xml.NewDecoder(strings.NewReader(xmlData))
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			return xml.StartElement{}, fmt.Errorf("no root element found in XML document")
+		}
+		if err != nil {
+			return xml.StartElement{}, fmt.Errorf("error reading XML token: %w", err)
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Copy(), nil
+		}
+	}
+}
+
+// omeSchemaPrefix is the URI prefix shared by all OME schema namespaces.
+const omeSchemaPrefix = "http://www.openmicroscopy.org/Schemas/"
+
+// NormalizeOMEXMLNamespaces re-serialises OME-XML with a canonical set of
+// namespace prefixes: "ome" for the OME namespace, the lower-cased schema
+// name for other OME schemas (e.g. "spw", "sa", "bin"), "xsi" for XML
+// Schema instance and "ns1", "ns2", ... for any other namespace in order of
+// first use. Comments and insignificant whitespace are dropped.
+func NormalizeOMEXMLNamespaces(xmlData string) (string, error) {
+	root, err := parseXMLTree(xmlData)
+	if err != nil {
+		return "", err
+	}
+
+	prefixes := make(map[string]string)
+	used := make(map[string]bool)
+	other := 0
+	for _, uri := range collectNamespaces(root, make(map[string]bool), nil) {
+		prefix := canonicalPrefix(uri)
+		for prefix == "" || used[prefix] {
+			other++
+			prefix = fmt.Sprintf("ns%d", other)
+		}
+		prefixes[uri] = prefix
+		used[prefix] = true
+	}
+
+	return serializeXMLTree(root, prefixes), nil
+}
+
+// canonicalPrefix returns the conventional prefix for a namespace URI, or
+// "" when there is none.
+func canonicalPrefix(uri string) string {
+	switch uri {
+	case xsiNamespace:
+		return "xsi"
+	case xmlNamespace:
+		return "xml"
+	}
+
+	if rest, ok := strings.CutPrefix(uri, omeSchemaPrefix); ok {
+		schema, _, _ := strings.Cut(rest, "/")
+		switch schema {
+		case "":
+			return ""
+		case "BinaryFile":
+			return "bin"
+		}
+		return strings.ToLower(schema)
+	}
+
+	return ""
+}
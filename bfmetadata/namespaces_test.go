@@ -0,0 +1,22 @@
+package bfmetadata
+
+import "testing"
+
+func TestGetOMEXMLFormatGeneration(t *testing.T) {
+	tests := map[string]string{
+		omeNamespace2016:                OMEXMLGenerationCurrent,
+		omeSchemaPrefix + "OME/2013-06": OMEXMLGenerationLegacy,
+		legacyFCNamespace:               OMEXMLGenerationLegacy,
+		omeSchemaPrefix + "OME/2099-01": OMEXMLGenerationUnknown,
+		"http://example.org/not-ome":    OMEXMLGenerationUnknown,
+	}
+	for uri, want := range tests {
+		got, err := GetOMEXMLFormatGeneration(`<OME xmlns="` + uri + `"/>`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: got %q, want %q", uri, got, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// coverslipThicknessKeys lists vendor attribute names for the coverslip
+// thickness an objective is designed for.
+var coverslipThicknessKeys = []string{"DesignedCoverslipThickness", "CoverslipThickness", "CoverGlassThickness", "CoverSlipThickness"}
+
+// GetDesignedCoverslipThickness returns the coverslip thickness in
+// millimeters that an objective is corrected for, read from vendor-specific
+// custom attributes. Values without a unit are taken to be millimeters.
+func GetDesignedCoverslipThickness(objective Objective) (float64, error) {
+	custom := GetCustomAttributes(objective.CustomAttributes)
+
+	value, ok := firstValue(custom, coverslipThicknessKeys)
+	if !ok {
+		return 0, fmt.Errorf("objective %s has no designed coverslip thickness", objective.ID)
+	}
+
+	thickness, unit, err := splitValueUnit(value)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing coverslip thickness of objective %s: %w", objective.ID, err)
+	}
+	if unit == "" {
+		return thickness, nil
+	}
+
+	return convertLength(thickness, unit, "mm")
+}
+
+// NyquistCheck compares the lateral pixel size of a series with the Nyquist
+// sampling limit of its objective. Sizes are in micrometers.
+type NyquistCheck struct {
+	PixelSize        float64
+	NyquistPixelSize float64
+	Undersampled     bool
+	Warnings         []string
+}
+
+// GetNyquistSamplingCheck checks whether a series is sampled at or above
+// the lateral Nyquist rate λem / (4·NA) for its first channel with an
+// emission wavelength. It additionally warns when an objective designed for
+// a coverslip has no correction collar setting.
+func GetNyquistSamplingCheck(ome *OME, seriesIdx int) (NyquistCheck, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return NyquistCheck{}, err
+	}
+
+	objective, err := getObjectiveForImage(ome, image)
+	if err != nil {
+		return NyquistCheck{}, err
+	}
+	if objective.LensNA <= 0 {
+		return NyquistCheck{}, fmt.Errorf("objective %s has no numerical aperture", objective.ID)
+	}
+
+	size, err := getPhysicalSize(ome, seriesIdx)
+	if err != nil {
+		return NyquistCheck{}, err
+	}
+	if size.X <= 0 {
+		return NyquistCheck{}, fmt.Errorf("series %d has no physical pixel size", seriesIdx)
+	}
+	pixelSize, err := convertLength(size.X, size.XUnit, "µm")
+	if err != nil {
+		return NyquistCheck{}, err
+	}
+
+	var emissionNm float64
+	for _, channel := range image.Pixels.Channels {
+		if channel.EmissionWavelength > 0 {
+			if emissionNm, err = wavelengthNm(channel.EmissionWavelength, channel.EmissionWavelengthUnit); err != nil {
+				return NyquistCheck{}, err
+			}
+			break
+		}
+	}
+	if emissionNm <= 0 {
+		return NyquistCheck{}, fmt.Errorf("series %d has no channel emission wavelength", seriesIdx)
+	}
+
+	check := NyquistCheck{
+		PixelSize:        pixelSize,
+		NyquistPixelSize: emissionNm / 1000 / (4 * objective.LensNA),
+	}
+	if check.PixelSize > check.NyquistPixelSize {
+		check.Undersampled = true
+		check.Warnings = append(check.Warnings, fmt.Sprintf("pixel size %.3f µm exceeds Nyquist limit %.3f µm", check.PixelSize, check.NyquistPixelSize))
+	}
+
+	if thickness, err := GetDesignedCoverslipThickness(*objective); err == nil && thickness > 0 {
+		if image.ObjectiveSettings.CorrectionCollar == 0 {
+			check.Warnings = append(check.Warnings, fmt.Sprintf("objective %s is designed for %.2f mm coverslips but no correction collar setting is recorded", objective.ID, thickness))
+		}
+	}
+
+	return check, nil
+}
+
+// splitValueUnit splits strings such as "0.17 mm" or "170µm" into the
+// numeric value and its unit.
+func splitValueUnit(value string) (float64, string, error) {
+	value = strings.TrimSpace(value)
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '-' && r != '+' && r != 'e' && r != 'E'
+	})
+	if end == -1 {
+		end = len(value)
+	}
+
+	number, err := strconv.ParseFloat(value[:end], 64)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return number, strings.TrimSpace(value[end:]), nil
+}
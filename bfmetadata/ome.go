@@ -0,0 +1,520 @@
+package bfmetadata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type OME struct {
+	XMLName     xml.Name     `xml:"OME"`
+	Experiments []Experiment `xml:"Experiment"`
+	Plates      []Plate      `xml:"Plate"`
+	Screens     []Screen     `xml:"Screen"`
+
+	Experimenters      []Experimenter      `xml:"Experimenter"`
+	ExperimenterGroups []ExperimenterGroup `xml:"ExperimenterGroup"`
+
+	Instruments []Instrument `xml:"Instrument"`
+	Images      []Image      `xml:"Image"`
+
+	StructuredAnnotations StructuredAnnotations `xml:"StructuredAnnotations"`
+	ROIs                  []ROI                 `xml:"ROI"`
+}
+
+type Experiment struct {
+	ID          string `xml:"ID,attr,omitempty"`
+	Type        string `xml:"Type,attr,omitempty"`
+	Description string `xml:"Description,omitempty"`
+}
+
+type ExperimentRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+type Experimenter struct {
+	ID          string `xml:"ID,attr,omitempty"`
+	FirstName   string `xml:"FirstName,attr,omitempty"`
+	MiddleName  string `xml:"MiddleName,attr,omitempty"`
+	LastName    string `xml:"LastName,attr,omitempty"`
+	Email       string `xml:"Email,attr,omitempty"`
+	Institution string `xml:"Institution,attr,omitempty"`
+	UserName    string `xml:"UserName,attr,omitempty"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type ExperimenterRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+// ExperimenterGroup lists the members of a group; Leaders reference the
+// experimenters leading it.
+type ExperimenterGroup struct {
+	ID               string            `xml:"ID,attr,omitempty"`
+	Name             string            `xml:"Name,attr,omitempty"`
+	Description      string            `xml:"Description,omitempty"`
+	ExperimenterRefs []ExperimenterRef `xml:"ExperimenterRef"`
+	Leaders          []ExperimenterRef `xml:"Leader"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type ExperimenterGroupRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+type Plate struct {
+	ID                     string `xml:"ID,attr,omitempty"`
+	Name                   string `xml:"Name,attr,omitempty"`
+	Status                 string `xml:"Status,attr,omitempty"`
+	ExternalIdentifier     string `xml:"ExternalIdentifier,attr,omitempty"`
+	Rows                   int    `xml:"Rows,attr,omitempty"`
+	Columns                int    `xml:"Columns,attr,omitempty"`
+	RowNamingConvention    string `xml:"RowNamingConvention,attr,omitempty"`
+	ColumnNamingConvention string `xml:"ColumnNamingConvention,attr,omitempty"`
+	Description            string `xml:"Description,omitempty"`
+	Wells                  []Well `xml:"Well"`
+
+	AnnotationRefs    []AnnotationRef    `xml:"AnnotationRef"`
+	PlateAcquisitions []PlateAcquisition `xml:"PlateAcquisition"`
+}
+
+// PlateAcquisition is one acquisition run over a plate; WellSampleRefs list
+// the fields imaged during it.
+type PlateAcquisition struct {
+	ID                string          `xml:"ID,attr,omitempty"`
+	Name              string          `xml:"Name,attr,omitempty"`
+	StartTime         string          `xml:"StartTime,attr,omitempty"`
+	EndTime           string          `xml:"EndTime,attr,omitempty"`
+	MaximumFieldCount int             `xml:"MaximumFieldCount,attr,omitempty"`
+	Description       string          `xml:"Description,omitempty"`
+	WellSampleRefs    []WellSampleRef `xml:"WellSampleRef"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type WellSampleRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+type Well struct {
+	ID                 string       `xml:"ID,attr,omitempty"`
+	Column             int          `xml:"Column,attr"`
+	Row                int          `xml:"Row,attr"`
+	ExternalIdentifier string       `xml:"ExternalIdentifier,attr,omitempty"`
+	Type               string       `xml:"Type,attr,omitempty"`
+	WellSamples        []WellSample `xml:"WellSample"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type WellSample struct {
+	ID            string    `xml:"ID,attr,omitempty"`
+	Index         int       `xml:"Index,attr"`
+	PositionX     float64   `xml:"PositionX,attr,omitempty"`
+	PositionXUnit string    `xml:"PositionXUnit,attr,omitempty"`
+	PositionY     float64   `xml:"PositionY,attr,omitempty"`
+	PositionYUnit string    `xml:"PositionYUnit,attr,omitempty"`
+	Timepoint     string    `xml:"Timepoint,attr,omitempty"`
+	ImageRef      *ImageRef `xml:"ImageRef"`
+}
+
+type ImageRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+type Screen struct {
+	ID          string     `xml:"ID,attr,omitempty"`
+	Name        string     `xml:"Name,attr,omitempty"`
+	Description string     `xml:"Description,omitempty"`
+	PlateRefs   []PlateRef `xml:"PlateRef"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type PlateRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+type Instrument struct {
+	ID         string      `xml:"ID,attr,omitempty"`
+	Microscope *Microscope `xml:"Microscope"`
+
+	Lasers                   []Laser                   `xml:"Laser"`
+	Arcs                     []Arc                     `xml:"Arc"`
+	Filaments                []Filament                `xml:"Filament"`
+	LightEmittingDiodes      []LightEmittingDiode      `xml:"LightEmittingDiode"`
+	GenericExcitationSources []GenericExcitationSource `xml:"GenericExcitationSource"`
+
+	Detectors  []Detector  `xml:"Detector"`
+	Objectives []Objective `xml:"Objective"`
+	FilterSets []FilterSet `xml:"FilterSet"`
+	Filters    []Filter    `xml:"Filter"`
+}
+
+type Filter struct {
+	ID           string `xml:"ID,attr,omitempty"`
+	Manufacturer string `xml:"Manufacturer,attr,omitempty"`
+	Model        string `xml:"Model,attr,omitempty"`
+	SerialNumber string `xml:"SerialNumber,attr,omitempty"`
+	LotNumber    string `xml:"LotNumber,attr,omitempty"`
+	Type         string `xml:"Type,attr,omitempty"`
+	FilterWheel  string `xml:"FilterWheel,attr,omitempty"`
+}
+
+type FilterSet struct {
+	ID                   string      `xml:"ID,attr,omitempty"`
+	Manufacturer         string      `xml:"Manufacturer,attr,omitempty"`
+	Model                string      `xml:"Model,attr,omitempty"`
+	SerialNumber         string      `xml:"SerialNumber,attr,omitempty"`
+	LotNumber            string      `xml:"LotNumber,attr,omitempty"`
+	ExcitationFilterRefs []FilterRef `xml:"ExcitationFilterRef"`
+	EmissionFilterRefs   []FilterRef `xml:"EmissionFilterRef"`
+}
+
+type FilterRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+type FilterSetRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+// LightPath lists the filters in a channel's light path.
+type LightPath struct {
+	ExcitationFilterRefs []FilterRef `xml:"ExcitationFilterRef"`
+	EmissionFilterRefs   []FilterRef `xml:"EmissionFilterRef"`
+}
+
+// LightSource holds the attributes shared by all light sources.
+type LightSource struct {
+	ID           string  `xml:"ID,attr,omitempty"`
+	Manufacturer string  `xml:"Manufacturer,attr,omitempty"`
+	Model        string  `xml:"Model,attr,omitempty"`
+	SerialNumber string  `xml:"SerialNumber,attr,omitempty"`
+	LotNumber    string  `xml:"LotNumber,attr,omitempty"`
+	Power        float64 `xml:"Power,attr,omitempty"`
+	PowerUnit    string  `xml:"PowerUnit,attr,omitempty"`
+}
+
+type Laser struct {
+	LightSource
+	Type           string  `xml:"Type,attr,omitempty"`
+	LaserMedium    string  `xml:"LaserMedium,attr,omitempty"`
+	Wavelength     float64 `xml:"Wavelength,attr,omitempty"`
+	WavelengthUnit string  `xml:"WavelengthUnit,attr,omitempty"`
+}
+
+type Arc struct {
+	LightSource
+	Type string `xml:"Type,attr,omitempty"`
+}
+
+type Filament struct {
+	LightSource
+	Type string `xml:"Type,attr,omitempty"`
+}
+
+type LightEmittingDiode struct {
+	LightSource
+}
+
+type GenericExcitationSource struct {
+	LightSource
+}
+
+type Detector struct {
+	ID                string  `xml:"ID,attr,omitempty"`
+	Manufacturer      string  `xml:"Manufacturer,attr,omitempty"`
+	Model             string  `xml:"Model,attr,omitempty"`
+	SerialNumber      string  `xml:"SerialNumber,attr,omitempty"`
+	LotNumber         string  `xml:"LotNumber,attr,omitempty"`
+	Type              string  `xml:"Type,attr,omitempty"`
+	Gain              float64 `xml:"Gain,attr,omitempty"`
+	Voltage           float64 `xml:"Voltage,attr,omitempty"`
+	VoltageUnit       string  `xml:"VoltageUnit,attr,omitempty"`
+	Offset            float64 `xml:"Offset,attr,omitempty"`
+	Zoom              float64 `xml:"Zoom,attr,omitempty"`
+	AmplificationGain float64 `xml:"AmplificationGain,attr,omitempty"`
+}
+
+type Objective struct {
+	ID                      string  `xml:"ID,attr,omitempty"`
+	Manufacturer            string  `xml:"Manufacturer,attr,omitempty"`
+	Model                   string  `xml:"Model,attr,omitempty"`
+	SerialNumber            string  `xml:"SerialNumber,attr,omitempty"`
+	Correction              string  `xml:"Correction,attr,omitempty"`
+	Immersion               string  `xml:"Immersion,attr,omitempty"`
+	LensNA                  float64 `xml:"LensNA,attr,omitempty"`
+	NominalMagnification    float64 `xml:"NominalMagnification,attr,omitempty"`
+	CalibratedMagnification float64 `xml:"CalibratedMagnification,attr,omitempty"`
+	WorkingDistance         float64 `xml:"WorkingDistance,attr,omitempty"`
+	WorkingDistanceUnit     string  `xml:"WorkingDistanceUnit,attr,omitempty"`
+
+	CustomAttributes []xml.Attr `xml:",any,attr"`
+}
+
+type Microscope struct {
+	Type         string `xml:"Type,attr,omitempty"`
+	Manufacturer string `xml:"Manufacturer,attr,omitempty"`
+	Model        string `xml:"Model,attr,omitempty"`
+	SerialNumber string `xml:"SerialNumber,attr,omitempty"`
+	LotNumber    string `xml:"LotNumber,attr,omitempty"`
+}
+
+type Image struct {
+	ID                   string                `xml:"ID,attr,omitempty"`
+	Name                 string                `xml:"Name,attr,omitempty"`
+	AcquisitionDate      time.Time             `xml:"-"`
+	AcquisitionDateRaw   string                `xml:"AcquisitionDate,omitempty"`
+	ExperimenterRef      *ExperimenterRef      `xml:"ExperimenterRef"`
+	ExperimentRef        *ExperimentRef        `xml:"ExperimentRef"`
+	ExperimenterGroupRef *ExperimenterGroupRef `xml:"ExperimenterGroupRef"`
+	InstrumentRef        *InstrumentRef        `xml:"InstrumentRef"`
+	ObjectiveSettings    *ObjectiveSettings    `xml:"ObjectiveSettings"`
+	Pixels               Pixels                `xml:"Pixels"`
+	ROIRefs              []ROIRef              `xml:"ROIRef"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+// UnmarshalXML decodes an Image and parses AcquisitionDate from its raw
+// value. A missing or unparseable date leaves AcquisitionDate zero; the raw
+// value is kept in AcquisitionDateRaw.
+func (i *Image) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type image Image
+	if err := d.DecodeElement((*image)(i), &start); err != nil {
+		return err
+	}
+
+	if i.AcquisitionDateRaw != "" {
+		i.AcquisitionDate, _ = parseAcquisitionDate(i.AcquisitionDateRaw)
+	}
+
+	return nil
+}
+
+type InstrumentRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+type ObjectiveSettings struct {
+	ID               string  `xml:"ID,attr,omitempty"`
+	CorrectionCollar float64 `xml:"CorrectionCollar,attr,omitempty"`
+	Medium           string  `xml:"Medium,attr,omitempty"`
+	RefractiveIndex  float64 `xml:"RefractiveIndex,attr,omitempty"`
+}
+
+type Pixels struct {
+	BigEndian         string     `xml:"BigEndian,attr,omitempty"`
+	DimensionOrder    string     `xml:"DimensionOrder,attr,omitempty"`
+	ID                string     `xml:"ID,attr,omitempty"`
+	Interleaved       string     `xml:"Interleaved,attr,omitempty"`
+	PhysicalSizeX     float64    `xml:"-"`
+	PhysicalSizeXRaw  string     `xml:"PhysicalSizeX,attr,omitempty"`
+	PhysicalSizeXUnit string     `xml:"PhysicalSizeXUnit,attr,omitempty"`
+	PhysicalSizeY     float64    `xml:"-"`
+	PhysicalSizeYRaw  string     `xml:"PhysicalSizeY,attr,omitempty"`
+	PhysicalSizeYUnit string     `xml:"PhysicalSizeYUnit,attr,omitempty"`
+	PhysicalSizeZ     float64    `xml:"-"`
+	PhysicalSizeZRaw  string     `xml:"PhysicalSizeZ,attr,omitempty"`
+	PhysicalSizeZUnit string     `xml:"PhysicalSizeZUnit,attr,omitempty"`
+	SignificantBits   int        `xml:"SignificantBits,attr,omitempty"`
+	SizeC             int        `xml:"SizeC,attr"`
+	SizeT             int        `xml:"SizeT,attr"`
+	SizeX             int        `xml:"SizeX,attr"`
+	SizeY             int        `xml:"SizeY,attr"`
+	SizeZ             int        `xml:"SizeZ,attr"`
+	Type              PixelType  `xml:"Type,attr,omitempty"`
+	Channels          []Channel  `xml:"Channel"`
+	TiffData          []TiffData `xml:"TiffData"`
+	Planes            []Plane    `xml:"Plane"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+
+	// sizeErrors records physical sizes that could not be parsed, so they can
+	// be logged once the whole document has been decoded.
+	sizeErrors []error
+}
+
+// UnmarshalXML decodes Pixels and parses the physical sizes from their raw
+// attribute values. A missing or unparseable physical size is 0; the raw
+// value is kept and the parse error recorded in sizeErrors.
+func (p *Pixels) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type pixels Pixels
+	if err := d.DecodeElement((*pixels)(p), &start); err != nil {
+		return err
+	}
+
+	sizes := []struct {
+		dst *float64
+		raw string
+	}{{&p.PhysicalSizeX, p.PhysicalSizeXRaw}, {&p.PhysicalSizeY, p.PhysicalSizeYRaw}, {&p.PhysicalSizeZ, p.PhysicalSizeZRaw}}
+	for _, size := range sizes {
+		f, err := parsePhysicalSize(size.raw)
+		if err != nil {
+			p.sizeErrors = append(p.sizeErrors, err)
+		}
+		*size.dst = f
+	}
+
+	return nil
+}
+
+// parsePhysicalSize parses a PhysicalSize attribute; an empty value is 0.
+func parsePhysicalSize(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing physical size %q: %w", value, err)
+	}
+
+	return f, nil
+}
+
+type Channel struct {
+	ID                       string         `xml:"ID,attr,omitempty"`
+	Name                     string         `xml:"Name,attr,omitempty"`
+	SamplesPerPixel          int            `xml:"SamplesPerPixel,attr,omitempty"`
+	IlluminationType         string         `xml:"IlluminationType,attr,omitempty"`
+	ContrastMethod           ContrastMethod `xml:"ContrastMethod,attr,omitempty"`
+	ExcitationWavelength     float64        `xml:"ExcitationWavelength,attr,omitempty"`
+	ExcitationWavelengthUnit string         `xml:"ExcitationWavelengthUnit,attr,omitempty"`
+	EmissionWavelength       float64        `xml:"EmissionWavelength,attr,omitempty"`
+	EmissionWavelengthUnit   string         `xml:"EmissionWavelengthUnit,attr,omitempty"`
+	Fluor                    string         `xml:"Fluor,attr,omitempty"`
+	PinholeSize              float64        `xml:"PinholeSize,attr,omitempty"`
+	PinholeSizeUnit          string         `xml:"PinholeSizeUnit,attr,omitempty"`
+	Color                    string         `xml:"Color,attr,omitempty"`
+	SignificantBits          int            `xml:"SignificantBits,attr,omitempty"`
+
+	DetectorSettings *DetectorSettings `xml:"DetectorSettings"`
+	FilterSetRef     *FilterSetRef     `xml:"FilterSetRef"`
+	LightPath        *LightPath        `xml:"LightPath"`
+	AnnotationRefs   []AnnotationRef   `xml:"AnnotationRef"`
+	CustomAttributes []xml.Attr        `xml:",any,attr"`
+}
+
+type DetectorSettings struct {
+	ID          string  `xml:"ID,attr,omitempty"`
+	Gain        float64 `xml:"Gain,attr,omitempty"`
+	Offset      float64 `xml:"Offset,attr,omitempty"`
+	Voltage     float64 `xml:"Voltage,attr,omitempty"`
+	VoltageUnit string  `xml:"VoltageUnit,attr,omitempty"`
+	Zoom        float64 `xml:"Zoom,attr,omitempty"`
+	ReadOutRate float64 `xml:"ReadOutRate,attr,omitempty"`
+	Binning     string  `xml:"Binning,attr,omitempty"`
+	Integration int     `xml:"Integration,attr,omitempty"`
+}
+
+type TiffData struct {
+	IFD        int   `xml:"IFD,attr,omitempty"`
+	FirstZ     int   `xml:"FirstZ,attr,omitempty"`
+	FirstT     int   `xml:"FirstT,attr,omitempty"`
+	FirstC     int   `xml:"FirstC,attr,omitempty"`
+	PlaneCount int   `xml:"PlaneCount,attr,omitempty"`
+	UUID       *UUID `xml:"UUID"`
+}
+
+type UUID struct {
+	FileName string `xml:"FileName,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+// Plane holds the acquisition details of one plane. PositionX, PositionY
+// and PositionZ are nil when the plane does not record that stage
+// coordinate.
+type Plane struct {
+	TheZ             int      `xml:"TheZ,attr"`
+	TheC             int      `xml:"TheC,attr"`
+	TheT             int      `xml:"TheT,attr"`
+	DeltaT           float64  `xml:"DeltaT,attr,omitempty"`
+	DeltaTUnit       string   `xml:"DeltaTUnit,attr,omitempty"`
+	ExposureTime     float64  `xml:"ExposureTime,attr,omitempty"`
+	ExposureTimeUnit string   `xml:"ExposureTimeUnit,attr,omitempty"`
+	PositionX        *float64 `xml:"PositionX,attr,omitempty"`
+	PositionXUnit    string   `xml:"PositionXUnit,attr,omitempty"`
+	PositionY        *float64 `xml:"PositionY,attr,omitempty"`
+	PositionYUnit    string   `xml:"PositionYUnit,attr,omitempty"`
+	PositionZ        *float64 `xml:"PositionZ,attr,omitempty"`
+	PositionZUnit    string   `xml:"PositionZUnit,attr,omitempty"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type AnnotationRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+// getPlate returns the plate at the given index.
+func getPlate(ome *OME, plateIdx int) (*Plate, error) {
+	if ome == nil {
+		return nil, fmt.Errorf("no OME metadata provided")
+	}
+	if plateIdx < 0 || plateIdx >= len(ome.Plates) {
+		return nil, fmt.Errorf("plate index %d out of range (%d plates)", plateIdx, len(ome.Plates))
+	}
+
+	return &ome.Plates[plateIdx], nil
+}
+
+// getImage returns the image for the given series index.
+func getImage(ome *OME, seriesIdx int) (*Image, error) {
+	if ome == nil {
+		return nil, fmt.Errorf("no OME metadata provided")
+	}
+	if seriesIdx < 0 || seriesIdx >= len(ome.Images) {
+		return nil, fmt.Errorf("series index %d out of range (%d series)", seriesIdx, len(ome.Images))
+	}
+
+	return &ome.Images[seriesIdx], nil
+}
+
+// getChannel returns the channel for the given series and channel index.
+func getChannel(ome *OME, seriesIdx, channelIdx int) (*Channel, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := image.Pixels.Channels
+	if channelIdx < 0 || channelIdx >= len(channels) {
+		return nil, fmt.Errorf("channel index %d out of range (%d channels in series %d)", channelIdx, len(channels), seriesIdx)
+	}
+
+	return &channels[channelIdx], nil
+}
+
+// getImageByID returns the image with the given ID.
+func getImageByID(ome *OME, imageID string) (*Image, error) {
+	if ome == nil {
+		return nil, fmt.Errorf("no OME metadata provided")
+	}
+
+	for i := range ome.Images {
+		if ome.Images[i].ID == imageID {
+			return &ome.Images[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("image %s not found", imageID)
+}
+
+// findPlane returns the plane of an image with the given Z, C and T indices.
+func findPlane(image *Image, z, c, t int) (*Plane, error) {
+	for i := range image.Pixels.Planes {
+		plane := &image.Pixels.Planes[i]
+		if plane.TheZ == z && plane.TheC == c && plane.TheT == t {
+			return plane, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no plane with Z=%d, C=%d, T=%d in image %s", z, c, t, image.ID)
+}
@@ -0,0 +1,23 @@
+package bfmetadata
+
+import "strings"
+
+// GetOMETIFFFileUUIDs returns the file name to UUID mapping declared by the
+// TiffData elements of a multi-file OME-TIFF set.
+func GetOMETIFFFileUUIDs(ome *OME) map[string]string {
+	uuids := make(map[string]string)
+	if ome == nil {
+		return uuids
+	}
+
+	for _, image := range ome.Images {
+		for _, tiffData := range image.Pixels.TiffData {
+			if tiffData.UUID == nil || tiffData.UUID.FileName == "" {
+				continue
+			}
+			uuids[tiffData.UUID.FileName] = strings.TrimSpace(tiffData.UUID.Value)
+		}
+	}
+
+	return uuids
+}
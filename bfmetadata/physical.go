@@ -0,0 +1,61 @@
+package bfmetadata
+
+import "fmt"
+
+// GetPhysicalFieldOfView returns the physical extent of a series along X, Y
+// and Z converted to unit, computed as PhysicalSize * Size for each axis.
+// Depth is 0 when PhysicalSizeZ is not recorded.
+func GetPhysicalFieldOfView(ome *OME, seriesIdx int, unit string) (widthX, heightY, depthZ float64, err error) {
+	size, err := getPhysicalSize(ome, seriesIdx)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if size.X <= 0 || size.Y <= 0 {
+		return 0, 0, 0, fmt.Errorf("series %d has no physical pixel size", seriesIdx)
+	}
+
+	p := ome.Images[seriesIdx].Pixels
+	if widthX, err = convertLength(size.X*float64(p.SizeX), size.XUnit, unit); err != nil {
+		return 0, 0, 0, err
+	}
+	if heightY, err = convertLength(size.Y*float64(p.SizeY), size.YUnit, unit); err != nil {
+		return 0, 0, 0, err
+	}
+	if depthZ, err = convertLength(size.Z*float64(p.SizeZ), size.ZUnit, unit); err != nil {
+		return 0, 0, 0, err
+	}
+
+	return widthX, heightY, depthZ, nil
+}
+
+// GetPixelAspectRatio returns PhysicalSizeX / PhysicalSizeY for a series.
+// It returns 1 when both sizes are unset or equal, and logs a warning for
+// ratios outside [0.9, 1.1].
+func GetPixelAspectRatio(ome *OME, seriesIdx int) (float64, error) {
+	size, err := getPhysicalSize(ome, seriesIdx)
+	if err != nil {
+		return 0, err
+	}
+
+	if size.X == 0 && size.Y == 0 {
+		return 1, nil
+	}
+	if size.X <= 0 || size.Y <= 0 {
+		return 0, fmt.Errorf("series %d has incomplete physical pixel size (X=%g, Y=%g)", seriesIdx, size.X, size.Y)
+	}
+
+	y, err := convertLength(size.Y, size.YUnit, size.XUnit)
+	if err != nil {
+		return 0, err
+	}
+	if size.X == y {
+		return 1, nil
+	}
+
+	ratio := size.X / y
+	if ratio < 0.9 || ratio > 1.1 {
+		logf(LogLevelWarn, "series %d has non-square pixels with aspect ratio %.3f", seriesIdx, ratio)
+	}
+
+	return ratio, nil
+}
@@ -0,0 +1,36 @@
+package bfmetadata
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetPixelAspectRatioUnits(t *testing.T) {
+	tests := []struct {
+		name         string
+		x, y         float64
+		xUnit, yUnit string
+		want         float64
+	}{
+		{"same unit", 0.2, 0.1, "µm", "µm", 2},
+		{"default X unit", 0.1, 100, "", "nm", 1},
+		{"default Y unit", 200, 0.1, "nm", "", 2},
+		{"both default", 0.1, 0.2, "", "", 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ome := &OME{Images: []Image{{Pixels: Pixels{
+				PhysicalSizeX: tt.x, PhysicalSizeXUnit: tt.xUnit,
+				PhysicalSizeY: tt.y, PhysicalSizeYUnit: tt.yUnit,
+			}}}}
+
+			got, err := GetPixelAspectRatio(ome, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package bfmetadata
+
+import "errors"
+
+// ErrInsufficientDataForAiryCalculation is returned by GetChannelPinholeAiry
+// when the pinhole size, emission wavelength or objective NA is missing.
+var ErrInsufficientDataForAiryCalculation = errors.New("insufficient data to compute pinhole size in Airy units")
+
+// GetChannelPinholeAiry returns the pinhole size of a channel in Airy units,
+// computed as pinholeSize / (1.22 * emissionWavelength / NA).
+func GetChannelPinholeAiry(ome *OME, seriesIdx, channelIdx int) (float64, error) {
+	channel, err := getChannel(ome, seriesIdx, channelIdx)
+	if err != nil {
+		return 0, err
+	}
+	if channel.PinholeSize <= 0 || channel.EmissionWavelength <= 0 {
+		return 0, ErrInsufficientDataForAiryCalculation
+	}
+
+	objective, err := getObjectiveForImage(ome, &ome.Images[seriesIdx])
+	if err != nil || objective.LensNA <= 0 {
+		return 0, ErrInsufficientDataForAiryCalculation
+	}
+
+	pinhole, err := convertLength(channel.PinholeSize, channel.PinholeSizeUnit, "µm")
+	if err != nil {
+		return 0, err
+	}
+	emissionNm, err := wavelengthNm(channel.EmissionWavelength, channel.EmissionWavelengthUnit)
+	if err != nil {
+		return 0, err
+	}
+
+	airyDiameter := 1.22 * (emissionNm / 1000) / objective.LensNA
+	return pinhole / airyDiameter, nil
+}
@@ -0,0 +1,97 @@
+package bfmetadata
+
+import "fmt"
+
+// PixelType is the OME Pixels Type enumeration.
+type PixelType string
+
+const (
+	PixelTypeInt8          PixelType = "int8"
+	PixelTypeInt16         PixelType = "int16"
+	PixelTypeInt32         PixelType = "int32"
+	PixelTypeUint8         PixelType = "uint8"
+	PixelTypeUint16        PixelType = "uint16"
+	PixelTypeUint32        PixelType = "uint32"
+	PixelTypeFloat         PixelType = "float"
+	PixelTypeDouble        PixelType = "double"
+	PixelTypeComplex       PixelType = "complex"
+	PixelTypeDoubleComplex PixelType = "double-complex"
+	PixelTypeBit           PixelType = "bit"
+)
+
+// validPixelTypes lists the PixelType values defined by the OME schema.
+var validPixelTypes = map[PixelType]bool{
+	PixelTypeInt8:          true,
+	PixelTypeInt16:         true,
+	PixelTypeInt32:         true,
+	PixelTypeUint8:         true,
+	PixelTypeUint16:        true,
+	PixelTypeUint32:        true,
+	PixelTypeFloat:         true,
+	PixelTypeDouble:        true,
+	PixelTypeComplex:       true,
+	PixelTypeDoubleComplex: true,
+	PixelTypeBit:           true,
+}
+
+// BytesPerPixel returns the storage size of one sample of type t, or 0 for
+// an unknown type. Bit data is stored one sample per byte, as Bio-Formats
+// returns it.
+func (t PixelType) BytesPerPixel() int {
+	switch t {
+	case PixelTypeInt8, PixelTypeUint8, PixelTypeBit:
+		return 1
+	case PixelTypeInt16, PixelTypeUint16:
+		return 2
+	case PixelTypeInt32, PixelTypeUint32, PixelTypeFloat:
+		return 4
+	case PixelTypeDouble, PixelTypeComplex:
+		return 8
+	case PixelTypeDoubleComplex:
+		return 16
+	}
+
+	return 0
+}
+
+// GetPixelsByteSize returns the size in bytes of the uncompressed pixel data
+// of a series: SizeX * SizeY * SizeZ * SizeT times the samples per plane
+// (see GetTotalSamplesPerPlane) times the bytes per sample.
+func GetPixelsByteSize(ome *OME, seriesIdx int) (int64, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return 0, err
+	}
+
+	p := image.Pixels
+	bytesPerPixel := p.Type.BytesPerPixel()
+	if bytesPerPixel == 0 {
+		return 0, fmt.Errorf("unsupported pixel type %q in series %d", p.Type, seriesIdx)
+	}
+
+	samples, err := GetTotalSamplesPerPlane(ome, seriesIdx)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(p.SizeX) * int64(p.SizeY) * int64(p.SizeZ) * int64(p.SizeT) * int64(samples) * int64(bytesPerPixel), nil
+}
+
+// GetPixelsByteCountAllSeries returns the summed GetPixelsByteSize of every
+// series.
+func GetPixelsByteCountAllSeries(ome *OME) (int64, error) {
+	if ome == nil {
+		return 0, fmt.Errorf("no OME metadata provided")
+	}
+
+	var total int64
+	for i := range ome.Images {
+		size, err := GetPixelsByteSize(ome, i)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+
+	return total, nil
+}
@@ -0,0 +1,54 @@
+package bfmetadata
+
+import (
+	"context"
+	"sort"
+)
+
+// GetPlanesForChannel returns the planes of a series that belong to
+// channelIdx, sorted by (TheT, TheZ).
+func GetPlanesForChannel(ome *OME, seriesIdx, channelIdx int) ([]Plane, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	var planes []Plane
+	for _, plane := range image.Pixels.Planes {
+		if plane.TheC == channelIdx {
+			planes = append(planes, plane)
+		}
+	}
+
+	sort.SliceStable(planes, func(i, j int) bool {
+		if planes[i].TheT != planes[j].TheT {
+			return planes[i].TheT < planes[j].TheT
+		}
+		return planes[i].TheZ < planes[j].TheZ
+	})
+
+	return planes, nil
+}
+
+// GetPlanes is a wrapper around the default Client's GetPlanes.
+func GetPlanes(ctx context.Context, filePath string, seriesIdx int) ([]Plane, []TiffData, error) {
+	return defaultClient.GetPlanes(ctx, filePath, seriesIdx)
+}
+
+// GetPlanes extracts the OME-XML of filePath and returns the Plane and
+// TiffData elements of a series in document order. Planes carry the
+// per-frame DeltaT, exposure time and stage position; TiffData maps planes
+// to the IFDs holding their pixels.
+func (c *Client) GetPlanes(ctx context.Context, filePath string, seriesIdx int) ([]Plane, []TiffData, error) {
+	metadata, err := c.getParsedMetadata(ctx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	image, err := getImage(metadata, seriesIdx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return image.Pixels.Planes, image.Pixels.TiffData, nil
+}
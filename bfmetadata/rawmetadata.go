@@ -0,0 +1,224 @@
+package bfmetadata
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Known raw metadata keys carrying horizontal and vertical flip flags.
+var (
+	flipHKeys = []string{"FlipX", "Stage.FlipHorizontal", "FlipHorizontal", "Flip X", "HorizontalFlip"}
+	flipVKeys = []string{"FlipY", "Stage.FlipVertical", "FlipVertical", "Flip Y", "VerticalFlip"}
+)
+
+// GetIsFlipH reports whether the X axis was flipped during acquisition.
+// It returns false, nil when no known flip key is present.
+func GetIsFlipH(rawMetadata map[string]string) (bool, error) {
+	return lookupRawBool(rawMetadata, flipHKeys)
+}
+
+// GetIsFlipV reports whether the Y axis was flipped during acquisition.
+// It returns false, nil when no known flip key is present.
+func GetIsFlipV(rawMetadata map[string]string) (bool, error) {
+	return lookupRawBool(rawMetadata, flipVKeys)
+}
+
+// lookupRawBool finds the first matching key and parses its value as a boolean.
+func lookupRawBool(rawMetadata map[string]string, keys []string) (bool, error) {
+	key, value, ok := findRawValue(rawMetadata, keys)
+	if !ok {
+		return false, nil
+	}
+
+	b, err := parseRawBool(value)
+	if err != nil {
+		return false, fmt.Errorf("error parsing %s: %w", key, err)
+	}
+
+	return b, nil
+}
+
+// findRawValue returns the key and value of the first entry matching one of
+// the given patterns. Patterns are tried in order; an exact key match wins,
+// otherwise keys ending in the pattern (as Bio-Formats prefixes original
+// metadata keys with series or section names) are considered in sorted order.
+func findRawValue(rawMetadata map[string]string, patterns []string) (string, string, bool) {
+	if len(rawMetadata) == 0 {
+		return "", "", false
+	}
+
+	keys := make([]string, 0, len(rawMetadata))
+	for key := range rawMetadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, pattern := range patterns {
+		if value, ok := rawMetadata[pattern]; ok {
+			return pattern, value, true
+		}
+
+		lower := strings.ToLower(pattern)
+		for _, key := range keys {
+			if strings.HasSuffix(strings.ToLower(key), lower) {
+				return key, rawMetadata[key], true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// parseRawBool accepts the boolean spellings used by common acquisition software.
+func parseRawBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0", "":
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// ScanDirection describes whether lines were scanned in one or both directions.
+type ScanDirection int
+
+const (
+	ScanDirectionUnknown ScanDirection = iota
+	ScanDirectionUnidirectional
+	ScanDirectionBidirectional
+)
+
+func (d ScanDirection) String() string {
+	switch d {
+	case ScanDirectionUnidirectional:
+		return "Unidirectional"
+	case ScanDirectionBidirectional:
+		return "Bidirectional"
+	}
+
+	return "Unknown"
+}
+
+// Known raw metadata keys describing the line scan direction.
+var (
+	scanDirectionKeys = []string{"ScanDirection", "LineDirection", "Scan Direction"}
+	bidirectionalKeys = []string{"Bidirectional", "BidirectionalScan", "IsBidirectional"}
+)
+
+// Words of a scan direction value naming each direction.
+var scanDirectionTokens = map[string]ScanDirection{
+	"bidirectional":   ScanDirectionBidirectional,
+	"bi-directional":  ScanDirectionBidirectional,
+	"bi":              ScanDirectionBidirectional,
+	"two-way":         ScanDirectionBidirectional,
+	"unidirectional":  ScanDirectionUnidirectional,
+	"uni-directional": ScanDirectionUnidirectional,
+	"uni":             ScanDirectionUnidirectional,
+	"mono":            ScanDirectionUnidirectional,
+	"monodirectional": ScanDirectionUnidirectional,
+	"one-way":         ScanDirectionUnidirectional,
+}
+
+// GetScanDirection reports the line scan direction recorded in raw metadata.
+// It returns ScanDirectionUnknown, nil when no known key is present.
+func GetScanDirection(rawMetadata map[string]string) (ScanDirection, error) {
+	if key, value, ok := findRawValue(rawMetadata, scanDirectionKeys); ok {
+		words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+		})
+		for _, word := range words {
+			if direction, ok := scanDirectionTokens[word]; ok {
+				return direction, nil
+			}
+		}
+		return ScanDirectionUnknown, fmt.Errorf("unrecognised scan direction %q in %s", value, key)
+	}
+
+	if _, _, ok := findRawValue(rawMetadata, bidirectionalKeys); ok {
+		bidirectional, err := lookupRawBool(rawMetadata, bidirectionalKeys)
+		if err != nil {
+			return ScanDirectionUnknown, err
+		}
+		if bidirectional {
+			return ScanDirectionBidirectional, nil
+		}
+		return ScanDirectionUnidirectional, nil
+	}
+
+	return ScanDirectionUnknown, nil
+}
+
+// originalMetadataHeader matches the lines showinf prints before each block
+// of original metadata, e.g. "Global metadata:" or "Series #0 metadata:".
+var originalMetadataHeader = regexp.MustCompile(`(?i)^(original|global|series #\d+) metadata:?$`)
+
+// GetOriginalMetadata is a wrapper around the default Client's GetOriginalMetadata.
+func GetOriginalMetadata(ctx context.Context, filePath string) (map[string]string, error) {
+	return defaultClient.GetOriginalMetadata(ctx, filePath)
+}
+
+// GetOriginalMetadata runs showinf -nopix against filePath and returns the
+// vendor-specific key/value pairs printed in its original metadata blocks.
+// These often hold settings that have no OME-XML equivalent.
+func (c *Client) GetOriginalMetadata(ctx context.Context, filePath string) (map[string]string, error) {
+	if err := checkInputFile(filePath); err != nil {
+		return nil, err
+	}
+
+	output, _, err := c.runTool(ctx, "showinf", filePath, "-nopix")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseOriginalMetadata(output), nil
+}
+
+// parseOriginalMetadata collects the key/value lines of every original
+// metadata block in showinf output. Keys and values are separated by a tab,
+// or by ": " in older Bio-Formats releases. The first value seen for a key
+// is kept.
+func parseOriginalMetadata(output string) map[string]string {
+	metadata := make(map[string]string)
+
+	inBlock := false
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case originalMetadataHeader.MatchString(trimmed):
+			inBlock = true
+			continue
+		case trimmed == "":
+			inBlock = false
+			continue
+		case !inBlock:
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, "\t")
+		if !ok {
+			key, value, ok = strings.Cut(trimmed, ": ")
+		}
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		if _, exists := metadata[key]; !exists && key != "" {
+			metadata[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return metadata
+}
@@ -0,0 +1,32 @@
+package bfmetadata
+
+import "testing"
+
+func TestGetScanDirection(t *testing.T) {
+	tests := []struct {
+		raw     map[string]string
+		want    ScanDirection
+		wantErr bool
+	}{
+		{map[string]string{"ScanDirection": "Bidirectional"}, ScanDirectionBidirectional, false},
+		{map[string]string{"LineDirection": "bi-directional scan"}, ScanDirectionBidirectional, false},
+		{map[string]string{"Scan Direction": "Unidirectional"}, ScanDirectionUnidirectional, false},
+		{map[string]string{"ScanDirection": "Mono"}, ScanDirectionUnidirectional, false},
+		{map[string]string{"ScanDirection": "one-way"}, ScanDirectionUnidirectional, false},
+		{map[string]string{"ScanDirection": "None"}, ScanDirectionUnknown, true},
+		{map[string]string{"ScanDirection": "Combined"}, ScanDirectionUnknown, true},
+		{map[string]string{"ScanMode": "Frame"}, ScanDirectionUnknown, false},
+		{map[string]string{"ScanMode": "Plane", "Bidirectional": "true"}, ScanDirectionBidirectional, false},
+		{map[string]string{"IsBidirectional": "false"}, ScanDirectionUnidirectional, false},
+		{map[string]string{}, ScanDirectionUnknown, false},
+	}
+	for _, tt := range tests {
+		got, err := GetScanDirection(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%v: got error %v, want error %v", tt.raw, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
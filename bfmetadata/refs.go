@@ -0,0 +1,197 @@
+package bfmetadata
+
+import "fmt"
+
+// ReferenceError describes a reference element whose ID does not resolve to
+// a declared element of the referenced type.
+type ReferenceError struct {
+	RefType       string
+	RefID         string
+	SourceElement string
+}
+
+func (e ReferenceError) Error() string {
+	return fmt.Sprintf("%s in %s references undeclared ID %s", e.RefType, e.SourceElement, e.RefID)
+}
+
+// ValidateOMEXMLReferences reports every reference in the OME metadata that
+// does not resolve to a declared element.
+func ValidateOMEXMLReferences(ome *OME) []ReferenceError {
+	if ome == nil {
+		return nil
+	}
+
+	instruments := make(map[string]bool)
+	objectives := make(map[string]bool)
+	detectors := make(map[string]bool)
+	filters := make(map[string]bool)
+	filterSets := make(map[string]bool)
+	for _, instrument := range ome.Instruments {
+		instruments[instrument.ID] = true
+		for _, detector := range instrument.Detectors {
+			detectors[detector.ID] = true
+		}
+		for _, filter := range instrument.Filters {
+			filters[filter.ID] = true
+		}
+		for _, filterSet := range instrument.FilterSets {
+			filterSets[filterSet.ID] = true
+		}
+		for _, objective := range instrument.Objectives {
+			objectives[objective.ID] = true
+		}
+	}
+
+	annotations := make(map[string]bool)
+	for _, annotation := range ome.StructuredAnnotations.All() {
+		annotations[annotation.ID] = true
+	}
+
+	experiments := make(map[string]bool)
+	for _, experiment := range ome.Experiments {
+		experiments[experiment.ID] = true
+	}
+
+	experimenters := make(map[string]bool)
+	for _, experimenter := range ome.Experimenters {
+		experimenters[experimenter.ID] = true
+	}
+
+	groups := make(map[string]bool)
+	for _, group := range ome.ExperimenterGroups {
+		groups[group.ID] = true
+	}
+
+	images := make(map[string]bool)
+	for _, image := range ome.Images {
+		images[image.ID] = true
+	}
+
+	plates := make(map[string]bool)
+	wellSamples := make(map[string]bool)
+	for _, plate := range ome.Plates {
+		plates[plate.ID] = true
+		for _, well := range plate.Wells {
+			for _, sample := range well.WellSamples {
+				wellSamples[sample.ID] = true
+			}
+		}
+	}
+
+	rois := make(map[string]bool)
+	for _, roi := range ome.ROIs {
+		rois[roi.ID] = true
+	}
+
+	var errs []ReferenceError
+	check := func(declared map[string]bool, refType, refID, source string) {
+		if !declared[refID] {
+			errs = append(errs, ReferenceError{RefType: refType, RefID: refID, SourceElement: source})
+		}
+	}
+	checkAnnotations := func(refs []AnnotationRef, source string) {
+		for _, ref := range refs {
+			check(annotations, "AnnotationRef", ref.ID, source)
+		}
+	}
+	checkFilters := func(refs []FilterRef, source string) {
+		for _, ref := range refs {
+			check(filters, "FilterRef", ref.ID, source)
+		}
+	}
+
+	for _, instrument := range ome.Instruments {
+		for _, filterSet := range instrument.FilterSets {
+			checkFilters(filterSet.ExcitationFilterRefs, filterSet.ID)
+			checkFilters(filterSet.EmissionFilterRefs, filterSet.ID)
+		}
+	}
+
+	for _, experimenter := range ome.Experimenters {
+		checkAnnotations(experimenter.AnnotationRefs, experimenter.ID)
+	}
+
+	for _, group := range ome.ExperimenterGroups {
+		checkAnnotations(group.AnnotationRefs, group.ID)
+		for _, ref := range group.ExperimenterRefs {
+			check(experimenters, "ExperimenterRef", ref.ID, group.ID)
+		}
+		for _, ref := range group.Leaders {
+			check(experimenters, "Leader", ref.ID, group.ID)
+		}
+	}
+
+	for _, plate := range ome.Plates {
+		checkAnnotations(plate.AnnotationRefs, plate.ID)
+		for _, well := range plate.Wells {
+			checkAnnotations(well.AnnotationRefs, well.ID)
+			for _, sample := range well.WellSamples {
+				if sample.ImageRef != nil {
+					check(images, "ImageRef", sample.ImageRef.ID, sample.ID)
+				}
+			}
+		}
+		for _, acquisition := range plate.PlateAcquisitions {
+			checkAnnotations(acquisition.AnnotationRefs, acquisition.ID)
+			for _, ref := range acquisition.WellSampleRefs {
+				check(wellSamples, "WellSampleRef", ref.ID, acquisition.ID)
+			}
+		}
+	}
+
+	for _, screen := range ome.Screens {
+		checkAnnotations(screen.AnnotationRefs, screen.ID)
+		for _, ref := range screen.PlateRefs {
+			check(plates, "PlateRef", ref.ID, screen.ID)
+		}
+	}
+
+	for _, image := range ome.Images {
+		if image.ExperimenterRef != nil {
+			check(experimenters, "ExperimenterRef", image.ExperimenterRef.ID, image.ID)
+		}
+		if image.ExperimenterGroupRef != nil {
+			check(groups, "ExperimenterGroupRef", image.ExperimenterGroupRef.ID, image.ID)
+		}
+		if image.ExperimentRef != nil {
+			check(experiments, "ExperimentRef", image.ExperimentRef.ID, image.ID)
+		}
+		if image.InstrumentRef != nil {
+			check(instruments, "InstrumentRef", image.InstrumentRef.ID, image.ID)
+		}
+		if image.ObjectiveSettings != nil {
+			check(objectives, "ObjectiveRef", image.ObjectiveSettings.ID, image.ID)
+		}
+		for _, ref := range image.ROIRefs {
+			check(rois, "ROIRef", ref.ID, image.ID)
+		}
+		checkAnnotations(image.AnnotationRefs, image.ID)
+		checkAnnotations(image.Pixels.AnnotationRefs, image.Pixels.ID)
+		for _, channel := range image.Pixels.Channels {
+			if channel.DetectorSettings != nil {
+				check(detectors, "DetectorRef", channel.DetectorSettings.ID, channel.ID)
+			}
+			if channel.FilterSetRef != nil {
+				check(filterSets, "FilterSetRef", channel.FilterSetRef.ID, channel.ID)
+			}
+			if channel.LightPath != nil {
+				checkFilters(channel.LightPath.ExcitationFilterRefs, channel.ID)
+				checkFilters(channel.LightPath.EmissionFilterRefs, channel.ID)
+			}
+			checkAnnotations(channel.AnnotationRefs, channel.ID)
+		}
+		for i, plane := range image.Pixels.Planes {
+			checkAnnotations(plane.AnnotationRefs, fmt.Sprintf("%s/Plane[%d]", image.Pixels.ID, i))
+		}
+	}
+
+	for _, roi := range ome.ROIs {
+		checkAnnotations(roi.AnnotationRefs, roi.ID)
+	}
+
+	for _, annotation := range ome.StructuredAnnotations.All() {
+		checkAnnotations(annotation.AnnotationRefs, annotation.ID)
+	}
+
+	return errs
+}
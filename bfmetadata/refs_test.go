@@ -0,0 +1,27 @@
+package bfmetadata
+
+import "testing"
+
+func TestValidateOMEXMLReferencesWellSampleRef(t *testing.T) {
+	ome, err := parseXML(`<OME xmlns="http://www.openmicroscopy.org/Schemas/OME/2016-06">
+  <Plate ID="Plate:0">
+    <Well ID="Well:0" Row="0" Column="0">
+      <WellSample ID="WellSample:0" Index="0"><ImageRef ID="Image:0"/></WellSample>
+    </Well>
+    <PlateAcquisition ID="PlateAcquisition:0">
+      <WellSampleRef ID="WellSample:0"/>
+      <WellSampleRef ID="WellSample:1"/>
+    </PlateAcquisition>
+  </Plate>
+  <Image ID="Image:0"><Pixels ID="Pixels:0" SizeX="1" SizeY="1" SizeZ="1" SizeC="1" SizeT="1"/></Image>
+</OME>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := ValidateOMEXMLReferences(ome)
+	want := ReferenceError{RefType: "WellSampleRef", RefID: "WellSample:1", SourceElement: "PlateAcquisition:0"}
+	if len(errs) != 1 || errs[0] != want {
+		t.Errorf("got %v, want [%v]", errs, want)
+	}
+}
@@ -0,0 +1,98 @@
+package bfmetadata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// maxRemoteOMEXMLSize limits how much of an OME-XML response is read into memory.
+const maxRemoteOMEXMLSize = 64 << 20
+
+// GetOMEXMLFromURL is a wrapper around the default Client's GetOMEXMLFromURL.
+func GetOMEXMLFromURL(ctx context.Context, url string) (string, error) {
+	return defaultClient.GetOMEXMLFromURL(ctx, url)
+}
+
+// GetOMEXMLFromURL downloads url and returns its OME-XML. The request is
+// bound to ctx, so its deadline covers both the download and any showinf run.
+//
+// OME-XML responses, recognised by their content type, extension or
+// leading bytes, are read into memory and rejected beyond 64 MiB. Any other
+// response is treated as a microscopy file: it is streamed to a temporary
+// file in the client's tool directory without a size limit, keeping the
+// URL's extension so Bio-Formats can detect the format, and passed to
+// showinf. The temporary file is removed afterwards.
+func (c *Client) GetOMEXMLFromURL(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request for %s: %w", url, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching %s: %s", url, resp.Status)
+	}
+
+	body := bufio.NewReader(resp.Body)
+	ext := strings.ToLower(path.Ext(resp.Request.URL.Path))
+	if isOMEXMLResponse(resp.Header.Get("Content-Type"), ext, body) {
+		data, err := io.ReadAll(io.LimitReader(body, maxRemoteOMEXMLSize+1))
+		if err != nil {
+			return "", fmt.Errorf("error reading %s: %w", url, err)
+		}
+		if len(data) > maxRemoteOMEXMLSize {
+			return "", fmt.Errorf("OME-XML at %s exceeds %d bytes", url, maxRemoteOMEXMLSize)
+		}
+		return string(data), nil
+	}
+
+	dir, err := c.prepare()
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(dir, "bfmetadata-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("error creating temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("error downloading %s: %w", url, err)
+	}
+
+	return c.GetOmexmlMetadata(ctx, tmp.Name(), AllSeries)
+}
+
+// isOMEXMLResponse reports whether a response holds OME-XML rather than an
+// image file, peeking at its first bytes when headers and extension are
+// inconclusive.
+func isOMEXMLResponse(contentType, ext string, body *bufio.Reader) bool {
+	if strings.Contains(contentType, "xml") {
+		return true
+	}
+	switch ext {
+	case ".xml", ".ome":
+		return true
+	}
+
+	head, _ := body.Peek(512)
+	head = bytes.TrimLeft(head, "\xef\xbb\xbf \t\r\n")
+	return bytes.HasPrefix(head, []byte("<?xml")) || bytes.HasPrefix(head, []byte("<OME"))
+}
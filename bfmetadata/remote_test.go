@@ -0,0 +1,51 @@
+package bfmetadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetOMEXMLFromURLDownloadsToToolDirectory(t *testing.T) {
+	installFakeJava(t, `for arg in "$@"; do
+  case "$arg" in *.czi) file=$arg ;; esac
+done
+if [ ! -f "$file" ]; then
+  echo "missing $file" >&2
+  exit 1
+fi
+echo "<?xml version=\"1.0\"?><OME xmlns=\"http://www.openmicroscopy.org/Schemas/OME/2016-06\"><Image ID=\"Image:0\" Name=\"$file\"/></OME>"
+`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("ZISRAWFILE not really a CZI"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	c := NewClient(WithTempDir(dir))
+	xmlData, err := c.GetOMEXMLFromURL(context.Background(), server.URL+"/images/sample.czi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ome, err := parseXML(xmlData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := ome.Images[0].Name
+	if filepath.Dir(path) != dir {
+		t.Errorf("download %s not in tool directory %s", path, dir)
+	}
+	if !strings.HasPrefix(filepath.Base(path), "bfmetadata-") || !strings.HasSuffix(path, ".czi") {
+		t.Errorf("unexpected temp file name %s", path)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("temp file %s not removed", path)
+	}
+}
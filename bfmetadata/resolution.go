@@ -0,0 +1,144 @@
+package bfmetadata
+
+import (
+	"context"
+	"fmt"
+)
+
+// PhysicalSize holds the physical pixel size of a series along each axis.
+type PhysicalSize struct {
+	X     float64
+	XUnit string
+	Y     float64
+	YUnit string
+	Z     float64
+	ZUnit string
+}
+
+// GetResolutionLevelDimensions returns the pixel dimensions of a pyramid
+// level. Bio-Formats flattens pyramids so that each sub-resolution follows
+// its full-resolution series as a separate Image; level 0 is the series
+// itself and level N is the Nth following Image with the same channels,
+// planes and pixel type and strictly decreasing XY size.
+func GetResolutionLevelDimensions(ome *OME, seriesIdx, level int) (sizeX, sizeY int, err error) {
+	if _, err := getImage(ome, seriesIdx); err != nil {
+		return 0, 0, err
+	}
+	if level < 0 {
+		return 0, 0, fmt.Errorf("resolution level %d out of range", level)
+	}
+
+	levels := resolutionLevels(ome, seriesIdx)
+	if level >= len(levels) {
+		return 0, 0, fmt.Errorf("resolution level %d out of range (%d levels in series %d)", level, len(levels), seriesIdx)
+	}
+
+	pixels := ome.Images[levels[level]].Pixels
+	return pixels.SizeX, pixels.SizeY, nil
+}
+
+// GetPhysicalSizeAtResolutionLevel returns the physical pixel size at a
+// pyramid level by scaling the full-resolution size by the ratio of the
+// level dimensions. The Z size is not affected by XY downsampling.
+func GetPhysicalSizeAtResolutionLevel(ome *OME, seriesIdx, level int) (PhysicalSize, error) {
+	base, err := getPhysicalSize(ome, seriesIdx)
+	if err != nil {
+		return PhysicalSize{}, err
+	}
+
+	baseX, baseY, err := GetResolutionLevelDimensions(ome, seriesIdx, 0)
+	if err != nil {
+		return PhysicalSize{}, err
+	}
+	levelX, levelY, err := GetResolutionLevelDimensions(ome, seriesIdx, level)
+	if err != nil {
+		return PhysicalSize{}, err
+	}
+	if levelX == 0 || levelY == 0 {
+		return PhysicalSize{}, fmt.Errorf("resolution level %d of series %d has zero size", level, seriesIdx)
+	}
+
+	scaled := base
+	scaled.X *= float64(baseX) / float64(levelX)
+	scaled.Y *= float64(baseY) / float64(levelY)
+
+	return scaled, nil
+}
+
+// ResolutionLevel describes one level of a series' resolution pyramid.
+type ResolutionLevel struct {
+	Level         int
+	SizeX, SizeY  int
+	PhysicalSizeX float64
+	PhysicalSizeY float64
+}
+
+// GetSeriesResolutionInfo is a wrapper around the default Client's GetSeriesResolutionInfo.
+func GetSeriesResolutionInfo(ctx context.Context, filePath string) ([][]ResolutionLevel, error) {
+	return defaultClient.GetSeriesResolutionInfo(ctx, filePath)
+}
+
+// GetSeriesResolutionInfo extracts the OME-XML of filePath once and returns
+// the pyramid levels of every series, indexed by series and then level, as
+// GetResolutionLevelDimensions and GetPhysicalSizeAtResolutionLevel describe
+// them.
+func (c *Client) GetSeriesResolutionInfo(ctx context.Context, filePath string) ([][]ResolutionLevel, error) {
+	metadata, err := c.getParsedMetadata(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info := make([][]ResolutionLevel, len(metadata.Images))
+	for seriesIdx := range metadata.Images {
+		for level, imageIdx := range resolutionLevels(metadata, seriesIdx) {
+			pixels := metadata.Images[imageIdx].Pixels
+			size, err := GetPhysicalSizeAtResolutionLevel(metadata, seriesIdx, level)
+			if err != nil {
+				return nil, err
+			}
+
+			info[seriesIdx] = append(info[seriesIdx], ResolutionLevel{
+				Level:         level,
+				SizeX:         pixels.SizeX,
+				SizeY:         pixels.SizeY,
+				PhysicalSizeX: size.X,
+				PhysicalSizeY: size.Y,
+			})
+		}
+	}
+
+	return info, nil
+}
+
+// resolutionLevels returns the image indices forming the pyramid of a series.
+func resolutionLevels(ome *OME, seriesIdx int) []int {
+	levels := []int{seriesIdx}
+	prev := ome.Images[seriesIdx].Pixels
+
+	for i := seriesIdx + 1; i < len(ome.Images); i++ {
+		p := ome.Images[i].Pixels
+		if p.SizeX >= prev.SizeX || p.SizeY >= prev.SizeY ||
+			p.SizeC != prev.SizeC || p.SizeZ != prev.SizeZ || p.SizeT != prev.SizeT || p.Type != prev.Type {
+			break
+		}
+		levels = append(levels, i)
+		prev = p
+	}
+
+	return levels
+}
+
+// getPhysicalSize returns the physical pixel size of a series.
+func getPhysicalSize(ome *OME, seriesIdx int) (PhysicalSize, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return PhysicalSize{}, err
+	}
+
+	p := image.Pixels
+	return PhysicalSize{
+		X: p.PhysicalSizeX, XUnit: p.PhysicalSizeXUnit,
+		Y: p.PhysicalSizeY, YUnit: p.PhysicalSizeYUnit,
+		Z: p.PhysicalSizeZ, ZUnit: p.PhysicalSizeZUnit,
+	}, nil
+}
@@ -0,0 +1,217 @@
+package bfmetadata
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type ROI struct {
+	ID          string `xml:"ID,attr,omitempty"`
+	Name        string `xml:"Name,attr,omitempty"`
+	Union       Union  `xml:"Union"`
+	Description string `xml:"Description,omitempty"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type ROIRef struct {
+	ID string `xml:"ID,attr,omitempty"`
+}
+
+// Union holds the shapes making up an ROI, grouped by shape type.
+type Union struct {
+	Rectangles []Rectangle `xml:"Rectangle"`
+	Ellipses   []Ellipse   `xml:"Ellipse"`
+	Polygons   []Polygon   `xml:"Polygon"`
+	Polylines  []Polyline  `xml:"Polyline"`
+	Lines      []Line      `xml:"Line"`
+	Points     []Point     `xml:"Point"`
+}
+
+// Shape holds the attributes shared by all ROI shapes. TheZ, TheT and TheC
+// are nil when the shape applies to every plane along that dimension.
+type Shape struct {
+	ID              string  `xml:"ID,attr,omitempty"`
+	TheZ            *int    `xml:"TheZ,attr,omitempty"`
+	TheT            *int    `xml:"TheT,attr,omitempty"`
+	TheC            *int    `xml:"TheC,attr,omitempty"`
+	Text            string  `xml:"Text,attr,omitempty"`
+	FillColor       string  `xml:"FillColor,attr,omitempty"`
+	StrokeColor     string  `xml:"StrokeColor,attr,omitempty"`
+	StrokeWidth     float64 `xml:"StrokeWidth,attr,omitempty"`
+	StrokeWidthUnit string  `xml:"StrokeWidthUnit,attr,omitempty"`
+
+	AnnotationRefs []AnnotationRef `xml:"AnnotationRef"`
+}
+
+type Rectangle struct {
+	Shape
+	X      float64 `xml:"X,attr"`
+	Y      float64 `xml:"Y,attr"`
+	Width  float64 `xml:"Width,attr"`
+	Height float64 `xml:"Height,attr"`
+}
+
+type Ellipse struct {
+	Shape
+	X       float64 `xml:"X,attr"`
+	Y       float64 `xml:"Y,attr"`
+	RadiusX float64 `xml:"RadiusX,attr"`
+	RadiusY float64 `xml:"RadiusY,attr"`
+}
+
+// Polygon is a closed shape. Points is the OME list of "x,y" pairs
+// separated by spaces.
+type Polygon struct {
+	Shape
+	Points string `xml:"Points,attr,omitempty"`
+}
+
+type Polyline struct {
+	Shape
+	Points string `xml:"Points,attr,omitempty"`
+}
+
+type Line struct {
+	Shape
+	X1 float64 `xml:"X1,attr"`
+	Y1 float64 `xml:"Y1,attr"`
+	X2 float64 `xml:"X2,attr"`
+	Y2 float64 `xml:"Y2,attr"`
+}
+
+type Point struct {
+	Shape
+	X float64 `xml:"X,attr"`
+	Y float64 `xml:"Y,attr"`
+}
+
+// GetROIs is a wrapper around the default Client's GetROIs.
+func GetROIs(ctx context.Context, filePath string) ([]ROI, error) {
+	return defaultClient.GetROIs(ctx, filePath)
+}
+
+// GetROIs extracts the OME-XML of filePath and returns its ROIs. Images link
+// to them through their ROIRefs.
+func (c *Client) GetROIs(ctx context.Context, filePath string) ([]ROI, error) {
+	metadata, err := c.getParsedMetadata(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata.ROIs, nil
+}
+
+// GetROIArea returns the summed area in µm² of the rectangles, ellipses and
+// polygons of an ROI, whose coordinates are in pixels. Lines, polylines and
+// points have no area and are skipped.
+func GetROIArea(roi ROI, physicalSizeXMicrons, physicalSizeYMicrons float64) (float64, error) {
+	if physicalSizeXMicrons <= 0 || physicalSizeYMicrons <= 0 {
+		return 0, fmt.Errorf("invalid physical pixel size %gx%g µm", physicalSizeXMicrons, physicalSizeYMicrons)
+	}
+
+	var area float64
+	for _, r := range roi.Union.Rectangles {
+		area += r.Width * physicalSizeXMicrons * r.Height * physicalSizeYMicrons
+	}
+	for _, e := range roi.Union.Ellipses {
+		area += math.Pi * e.RadiusX * physicalSizeXMicrons * e.RadiusY * physicalSizeYMicrons
+	}
+	for _, p := range roi.Union.Polygons {
+		points, err := parseShapePoints(p.Points)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing points of polygon %s: %w", p.ID, err)
+		}
+		area += polygonArea(scalePoints(points, physicalSizeXMicrons, physicalSizeYMicrons))
+	}
+
+	return area, nil
+}
+
+// GetROIPerimeter returns the summed perimeter in µm of the rectangles,
+// ellipses and polygons of an ROI, whose coordinates are in pixels. Ellipse
+// perimeters use Ramanujan's approximation.
+func GetROIPerimeter(roi ROI, physicalSizeXMicrons, physicalSizeYMicrons float64) (float64, error) {
+	if physicalSizeXMicrons <= 0 || physicalSizeYMicrons <= 0 {
+		return 0, fmt.Errorf("invalid physical pixel size %gx%g µm", physicalSizeXMicrons, physicalSizeYMicrons)
+	}
+
+	var perimeter float64
+	for _, r := range roi.Union.Rectangles {
+		perimeter += 2 * (r.Width*physicalSizeXMicrons + r.Height*physicalSizeYMicrons)
+	}
+	for _, e := range roi.Union.Ellipses {
+		a := e.RadiusX * physicalSizeXMicrons
+		b := e.RadiusY * physicalSizeYMicrons
+		perimeter += math.Pi * (3*(a+b) - math.Sqrt((3*a+b)*(a+3*b)))
+	}
+	for _, p := range roi.Union.Polygons {
+		points, err := parseShapePoints(p.Points)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing points of polygon %s: %w", p.ID, err)
+		}
+		perimeter += polygonPerimeter(scalePoints(points, physicalSizeXMicrons, physicalSizeYMicrons))
+	}
+
+	return perimeter, nil
+}
+
+// parseShapePoints parses an OME points list such as "1,2 3,4 5,6".
+func parseShapePoints(s string) ([][2]float64, error) {
+	var points [][2]float64
+	for _, pair := range strings.Fields(s) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			return nil, fmt.Errorf("invalid point %q", pair)
+		}
+		x, err := strconv.ParseFloat(xy[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %w", pair, err)
+		}
+		y, err := strconv.ParseFloat(xy[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %w", pair, err)
+		}
+		points = append(points, [2]float64{x, y})
+	}
+
+	return points, nil
+}
+
+func scalePoints(points [][2]float64, sx, sy float64) [][2]float64 {
+	scaled := make([][2]float64, len(points))
+	for i, p := range points {
+		scaled[i] = [2]float64{p[0] * sx, p[1] * sy}
+	}
+
+	return scaled
+}
+
+// polygonArea computes the area of a closed polygon with the shoelace formula.
+func polygonArea(points [][2]float64) float64 {
+	var sum float64
+	for i := range points {
+		j := (i + 1) % len(points)
+		sum += points[i][0]*points[j][1] - points[j][0]*points[i][1]
+	}
+
+	return math.Abs(sum) / 2
+}
+
+// polygonPerimeter sums the edge lengths of a closed polygon.
+func polygonPerimeter(points [][2]float64) float64 {
+	if len(points) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for i := range points {
+		j := (i + 1) % len(points)
+		sum += math.Hypot(points[j][0]-points[i][0], points[j][1]-points[i][1])
+	}
+
+	return sum
+}
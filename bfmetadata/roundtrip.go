@@ -0,0 +1,121 @@
+package bfmetadata
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// WriteOMEXML encodes ome as an indented OME-XML document and writes it to
+// w. The root element keeps the namespace ome was decoded with, or the
+// 2016-06 namespace if it has none. Elements and attributes the OME struct
+// does not model are not written.
+func WriteOMEXML(w io.Writer, ome *OME) error {
+	if ome == nil {
+		return fmt.Errorf("no OME metadata provided")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	space := ome.XMLName.Space
+	if space == "" {
+		space = omeNamespace2016
+	}
+	start := xml.StartElement{Name: xml.Name{Space: space, Local: "OME"}}
+	if err := encoder.EncodeElement(ome, start); err != nil {
+		return fmt.Errorf("error encoding OME-XML: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing OME-XML: %w", err)
+	}
+
+	return nil
+}
+
+// DiffOME compares two OME structs field by field and returns the paths of
+// the fields that differ, such as "Images[0].Pixels.SizeX". A slice whose
+// length differs is reported once and its common elements are compared.
+// Times are compared by instant and NaN equals NaN.
+func DiffOME(a, b *OME) []string {
+	var diffs []string
+	diffValues(reflect.ValueOf(a), reflect.ValueOf(b), "OME", &diffs)
+	return diffs
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func diffValues(a, b reflect.Value, path string, diffs *[]string) {
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*diffs = append(*diffs, path)
+			}
+			return
+		}
+		diffValues(a.Elem(), b.Elem(), path, diffs)
+	case reflect.Struct:
+		if a.Type() == timeType {
+			if !a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+				*diffs = append(*diffs, path)
+			}
+			return
+		}
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			diffValues(a.Field(i), b.Field(i), path+"."+field.Name, diffs)
+		}
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			*diffs = append(*diffs, path)
+		}
+		for i := 0; i < a.Len() && i < b.Len(); i++ {
+			diffValues(a.Index(i), b.Index(i), path+"["+strconv.Itoa(i)+"]", diffs)
+		}
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		if x != y && !(math.IsNaN(x) && math.IsNaN(y)) {
+			*diffs = append(*diffs, path)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*diffs = append(*diffs, path)
+		}
+	}
+}
+
+// TestOMEXMLRoundTripFidelity parses xmlData, writes it back with
+// WriteOMEXML and parses the result again. It reports whether both parses
+// are equal and, if not, the differing fields as returned by DiffOME.
+func TestOMEXMLRoundTripFidelity(xmlData string) (bool, []string, error) {
+	original, err := parseXML(xmlData)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOMEXML(&buf, original); err != nil {
+		return false, nil, err
+	}
+
+	reparsed, err := parseXML(buf.String())
+	if err != nil {
+		return false, nil, fmt.Errorf("error parsing serialized OME-XML: %w", err)
+	}
+
+	diffs := DiffOME(original, reparsed)
+	return len(diffs) == 0, diffs, nil
+}
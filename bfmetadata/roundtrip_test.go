@@ -0,0 +1,79 @@
+package bfmetadata
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestRoundTripFidelity(t *testing.T) {
+	equal, diffs, err := TestOMEXMLRoundTripFidelity(readFixture(t, "bioformats.ome.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal {
+		t.Errorf("round trip changed fields: %v", diffs)
+	}
+}
+
+func TestWriteOMEXML(t *testing.T) {
+	ome, err := parseXML(readFixture(t, "bioformats.ome.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOMEXML(&buf, ome); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`<OME xmlns="http://www.openmicroscopy.org/Schemas/OME/2016-06"`,
+		`<Value><OriginalMetadata><Key>Zoom X</Key><Value>2.0</Value></OriginalMetadata></Value>`,
+		`<Plane TheZ="0" TheC="0" TheT="0"`,
+		`X="0" Y="310.5"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %s", want)
+		}
+	}
+	for _, unwanted := range []string{`=""`, `Offset="0"`, `<AcquisitionDate></AcquisitionDate>`} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %s", unwanted)
+		}
+	}
+}
+
+func TestDiffOME(t *testing.T) {
+	a, err := parseXML(readFixture(t, "bioformats.ome.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := parseXML(readFixture(t, "bioformats.ome.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.Images[0].Pixels.SizeX = 512
+	b.StructuredAnnotations.XMLAnnotations[1].Value = ""
+	b.ROIs = nil
+
+	got := strings.Join(DiffOME(a, b), " ")
+	want := "OME.Images[0].Pixels.SizeX OME.StructuredAnnotations.XMLAnnotations[1].Value OME.ROIs"
+	if got != want {
+		t.Errorf("DiffOME = %q, want %q", got, want)
+	}
+}
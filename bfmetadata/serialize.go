@@ -0,0 +1,215 @@
+package bfmetadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// orderedField is one key of a serialised document. Value is a scalar, a
+// nested []orderedField or a []interface{} list of either.
+type orderedField struct {
+	Key   string
+	Value interface{}
+}
+
+// fields returns the metadata in canonical order, which follows the order
+// of the EssentialMetadata struct.
+func (m EssentialMetadata) fields() []orderedField {
+	channels := make([]interface{}, len(m.Channels))
+	for i, channel := range m.Channels {
+		channels[i] = channelFields(channel)
+	}
+
+	return []orderedField{
+		{"Series", m.Series},
+		{"Name", m.Name},
+		{"AcquisitionDate", m.AcquisitionDate},
+		{"DimensionOrder", m.DimensionOrder},
+		{"PhysicalSizeX", m.PhysicalSizeX},
+		{"PhysicalSizeXUnit", m.PhysicalSizeXUnit},
+		{"PhysicalSizeY", m.PhysicalSizeY},
+		{"PhysicalSizeYUnit", m.PhysicalSizeYUnit},
+		{"PhysicalSizeZ", m.PhysicalSizeZ},
+		{"PhysicalSizeZUnit", m.PhysicalSizeZUnit},
+		{"Size", []orderedField{
+			{"C", m.Size.C},
+			{"T", m.Size.T},
+			{"X", m.Size.X},
+			{"Y", m.Size.Y},
+			{"Z", m.Size.Z},
+		}},
+		{"PixelBitDepth", m.PixelBitDepth},
+		{"Channels", channels},
+		{"ObjectiveNA", m.ObjectiveNA},
+		{"ObjectiveMagnification", m.ObjectiveMagnification},
+		{"ExperimenterEmail", m.ExperimenterEmail},
+		{"Institution", m.Institution},
+	}
+}
+
+// channelFields returns the attributes of a channel in schema order.
+// References to other elements and custom attributes are left out.
+func channelFields(c Channel) []orderedField {
+	return []orderedField{
+		{"ID", c.ID},
+		{"Name", c.Name},
+		{"SamplesPerPixel", c.SamplesPerPixel},
+		{"IlluminationType", c.IlluminationType},
+		{"ContrastMethod", string(c.ContrastMethod)},
+		{"ExcitationWavelength", c.ExcitationWavelength},
+		{"ExcitationWavelengthUnit", c.ExcitationWavelengthUnit},
+		{"EmissionWavelength", c.EmissionWavelength},
+		{"EmissionWavelengthUnit", c.EmissionWavelengthUnit},
+		{"Fluor", c.Fluor},
+		{"PinholeSize", c.PinholeSize},
+		{"PinholeSizeUnit", c.PinholeSizeUnit},
+		{"Color", c.Color},
+		{"SignificantBits", c.SignificantBits},
+	}
+}
+
+// MarshalJSON encodes the metadata as a JSON object whose keys are always
+// written in the same order.
+func (m EssentialMetadata) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSONValue(&buf, m.fields()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeJSONValue(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case []orderedField:
+		buf.WriteByte('{')
+		for i, field := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(field.Key)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSONValue(buf, field.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("error encoding JSON value: %w", err)
+		}
+		buf.Write(data)
+	}
+
+	return nil
+}
+
+// WriteYAML writes the metadata to w as a YAML document with keys in the
+// same order as MarshalJSON. Strings are always double-quoted.
+func WriteYAML(w io.Writer, m *EssentialMetadata) error {
+	if m == nil {
+		return fmt.Errorf("no metadata provided")
+	}
+
+	var buf bytes.Buffer
+	if err := writeYAMLFields(&buf, m.fields(), 0); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing YAML: %w", err)
+	}
+
+	return nil
+}
+
+// writeYAMLFields writes one mapping line per field at the given indent.
+func writeYAMLFields(buf *bytes.Buffer, fields []orderedField, indent int) error {
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteString(strings.Repeat(" ", indent))
+		}
+		buf.WriteString(field.Key + ":")
+
+		switch v := field.Value.(type) {
+		case []orderedField:
+			buf.WriteString("\n" + strings.Repeat(" ", indent+2))
+			if err := writeYAMLFields(buf, v, indent+2); err != nil {
+				return err
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				buf.WriteString(" []\n")
+				continue
+			}
+			buf.WriteString("\n")
+			for _, item := range v {
+				buf.WriteString(strings.Repeat(" ", indent+2) + "- ")
+				if nested, ok := item.([]orderedField); ok {
+					if err := writeYAMLFields(buf, nested, indent+4); err != nil {
+						return err
+					}
+					continue
+				}
+				scalar, err := yamlScalar(item)
+				if err != nil {
+					return err
+				}
+				buf.WriteString(scalar + "\n")
+			}
+		default:
+			scalar, err := yamlScalar(v)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(" " + scalar + "\n")
+		}
+	}
+
+	return nil
+}
+
+// yamlScalar formats a scalar value. Quoted JSON strings are valid YAML
+// double-quoted scalars.
+func yamlScalar(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return ".nan", nil
+		case math.IsInf(v, 1):
+			return ".inf", nil
+		case math.IsInf(v, -1):
+			return "-.inf", nil
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case string:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("error encoding YAML value: %w", err)
+		}
+		return string(data), nil
+	}
+
+	return "", fmt.Errorf("unsupported YAML value of type %T", value)
+}
@@ -0,0 +1,147 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GroupSeriesByNamePattern groups series indices by the first capture group
+// of pattern matched against each Image name. Series whose names do not
+// match are left out.
+func GroupSeriesByNamePattern(ome *OME, pattern string) (map[string][]int, error) {
+	if ome == nil {
+		return nil, fmt.Errorf("no OME metadata provided")
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling series name pattern: %w", err)
+	}
+	if re.NumSubexp() < 1 {
+		return nil, fmt.Errorf("series name pattern %q has no capture group", pattern)
+	}
+
+	groups := make(map[string][]int)
+	for i, image := range ome.Images {
+		match := re.FindStringSubmatch(image.Name)
+		if match == nil {
+			continue
+		}
+		groups[match[1]] = append(groups[match[1]], i)
+	}
+
+	return groups, nil
+}
+
+// GroupSeriesByChannelSet groups series indices by their channel names. The
+// group key is the sorted, comma-separated list of channel names.
+func GroupSeriesByChannelSet(ome *OME) (map[string][]int, error) {
+	if ome == nil {
+		return nil, fmt.Errorf("no OME metadata provided")
+	}
+
+	groups := make(map[string][]int)
+	for i, image := range ome.Images {
+		names := make([]string, 0, len(image.Pixels.Channels))
+		for _, channel := range image.Pixels.Channels {
+			names = append(names, channel.Name)
+		}
+		sort.Strings(names)
+
+		key := strings.Join(names, ",")
+		groups[key] = append(groups[key], i)
+	}
+
+	return groups, nil
+}
+
+// GetCompatibleSeriesGroups groups series indices that can be analysed
+// together: series in a group have the same pixel type and PhysicalSizeX and
+// PhysicalSizeY within tolerance, given as a fraction of the larger size.
+// Sizes are compared in micrometers; a series whose size unit cannot be
+// converted is put in a group of its own. Each series is compared with the
+// first series of every earlier group and joins the first that matches; the
+// groups are ordered by their first series.
+func GetCompatibleSeriesGroups(ome *OME, tolerance float64) ([][]int, error) {
+	if ome == nil {
+		return nil, fmt.Errorf("no OME metadata provided")
+	}
+	if tolerance < 0 || math.IsNaN(tolerance) {
+		return nil, fmt.Errorf("invalid tolerance %g", tolerance)
+	}
+
+	// Unconvertible units give NaN, which is never within tolerance.
+	sizesX := make([]float64, len(ome.Images))
+	sizesY := make([]float64, len(ome.Images))
+	for i, image := range ome.Images {
+		p := image.Pixels
+		sizesX[i] = NormaliseToMicrons(p.PhysicalSizeX, p.PhysicalSizeXUnit)
+		sizesY[i] = NormaliseToMicrons(p.PhysicalSizeY, p.PhysicalSizeYUnit)
+	}
+
+	var groups [][]int
+	for i, image := range ome.Images {
+		joined := false
+		for g, group := range groups {
+			first := group[0]
+			if ome.Images[first].Pixels.Type == image.Pixels.Type &&
+				withinTolerance(sizesX[first], sizesX[i], tolerance) &&
+				withinTolerance(sizesY[first], sizesY[i], tolerance) {
+				groups[g] = append(groups[g], i)
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			groups = append(groups, []int{i})
+		}
+	}
+
+	return groups, nil
+}
+
+// withinTolerance reports whether a and b differ by at most tolerance as a
+// fraction of the larger magnitude.
+func withinTolerance(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// SeriesNameNotFoundError is returned when no series has the requested name.
+type SeriesNameNotFoundError struct {
+	Name string
+}
+
+func (e SeriesNameNotFoundError) Error() string {
+	return fmt.Sprintf("no series named %q", e.Name)
+}
+
+// GetSeriesNameToIndexMap maps each Image name to its series index. When
+// several series share a name the first one is kept and a warning is logged.
+func GetSeriesNameToIndexMap(ome *OME) map[string]int {
+	indices := make(map[string]int)
+	if ome == nil {
+		return indices
+	}
+
+	for i, image := range ome.Images {
+		if first, ok := indices[image.Name]; ok {
+			logf(LogLevelWarn, "series %d has the same name %q as series %d; keeping series %d", i, image.Name, first, first)
+			continue
+		}
+		indices[image.Name] = i
+	}
+
+	return indices
+}
+
+// GetSeriesIndexByName returns the index of the first series named name.
+func GetSeriesIndexByName(ome *OME, name string) (int, error) {
+	if idx, ok := GetSeriesNameToIndexMap(ome)[name]; ok {
+		return idx, nil
+	}
+
+	return -1, SeriesNameNotFoundError{Name: name}
+}
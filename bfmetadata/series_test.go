@@ -0,0 +1,57 @@
+package bfmetadata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetCompatibleSeriesGroups(t *testing.T) {
+	series := func(pixelType PixelType, x float64, xUnit string, y float64, yUnit string) Image {
+		return Image{Pixels: Pixels{
+			Type:          pixelType,
+			PhysicalSizeX: x, PhysicalSizeXUnit: xUnit,
+			PhysicalSizeY: y, PhysicalSizeYUnit: yUnit,
+		}}
+	}
+
+	tests := map[string]struct {
+		images []Image
+		want   [][]int
+	}{
+		"same unit": {
+			images: []Image{
+				series("uint16", 0.5, "µm", 0.5, "µm"),
+				series("uint16", 0.5001, "µm", 0.5, "µm"),
+				series("uint8", 0.5, "µm", 0.5, "µm"),
+			},
+			want: [][]int{{0, 1}, {2}},
+		},
+		"mixed units": {
+			images: []Image{
+				series("uint16", 0.5, "µm", 0.5, "µm"),
+				series("uint16", 500, "nm", 0.0005, "mm"),
+				series("uint16", 0.5, "nm", 0.5, "nm"),
+			},
+			want: [][]int{{0, 1}, {2}},
+		},
+		"unconvertible unit": {
+			images: []Image{
+				series("uint16", 0.5, "furlong", 0.5, "furlong"),
+				series("uint16", 0.5, "furlong", 0.5, "furlong"),
+				series("uint16", 0.5, "µm", 0.5, "µm"),
+			},
+			want: [][]int{{0}, {1}, {2}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			groups, err := GetCompatibleSeriesGroups(&OME{Images: tt.images}, 0.01)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(groups, tt.want) {
+				t.Errorf("got %v, want %v", groups, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package bfmetadata
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// ShowinfoFlag is a command line option accepted by showinf.
+type ShowinfoFlag struct {
+	Name        string
+	Description string
+}
+
+// showinfFlagPattern matches an option line of the showinf usage text, e.g.
+// "      -nopix: read metadata only, not pixels".
+var showinfFlagPattern = regexp.MustCompile(`^\s*(-\S+?):\s+(.*)$`)
+
+// GetShowinfoFlags is a wrapper around the default Client's GetShowinfoFlags.
+func GetShowinfoFlags(ctx context.Context) ([]ShowinfoFlag, error) {
+	return defaultClient.GetShowinfoFlags(ctx)
+}
+
+// GetShowinfoFlags runs showinf --help and returns the options listed in its
+// usage text, in the order printed, so callers can discover flags added by
+// newer Bio-Formats releases.
+func (c *Client) GetShowinfoFlags(ctx context.Context) ([]ShowinfoFlag, error) {
+	stdout, stderr, err := c.runTool(ctx, "showinf", "--help")
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// showinf exits with a non-zero status after printing its usage.
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, err
+	}
+
+	flags := parseShowinfFlags(stdout + "\n" + stderr)
+	if len(flags) == 0 {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("no flags found in showinf usage output")
+	}
+
+	return flags, nil
+}
+
+// parseShowinfFlags extracts option lines from showinf usage text.
+func parseShowinfFlags(output string) []ShowinfoFlag {
+	var flags []ShowinfoFlag
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if m := showinfFlagPattern.FindStringSubmatch(scanner.Text()); m != nil {
+			flags = append(flags, ShowinfoFlag{Name: m[1], Description: strings.TrimSpace(m[2])})
+		}
+	}
+
+	return flags
+}
@@ -0,0 +1,329 @@
+package bfmetadata
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// GridLayout describes a regular grid of stage positions.
+type GridLayout struct {
+	Rows    int
+	Columns int
+	OriginX float64
+	OriginY float64
+	StepX   float64
+	StepY   float64
+	Unit    string
+}
+
+// StagePosition is the stage position at which one plane was acquired. Z
+// is 0 and HasZ false when the plane records no PositionZ.
+type StagePosition struct {
+	Series           int
+	TheT, TheZ, TheC int
+	X, Y, Z          float64
+	HasZ             bool
+	Unit             string
+}
+
+// planePosition is the stage position of one plane, converted to a common
+// unit. plane is the index of the plane in its Pixels.
+type planePosition struct {
+	plane   int
+	x, y, z float64
+	hasZ    bool
+}
+
+// GetStagePositions is a wrapper around the default Client's GetStagePositions.
+func GetStagePositions(ctx context.Context, filePath string) ([]StagePosition, error) {
+	return defaultClient.GetStagePositions(ctx, filePath)
+}
+
+// GetStagePositions extracts the OME-XML of filePath and returns the stage
+// position of every plane of every series, sorted by series, TheT, TheZ and
+// TheC. Positions within a series are converted to the unit of its first
+// positioned plane. Planes without a PositionX and PositionY are skipped.
+func (c *Client) GetStagePositions(ctx context.Context, filePath string) ([]StagePosition, error) {
+	metadata, err := c.getParsedMetadata(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []StagePosition
+	for seriesIdx, image := range metadata.Images {
+		planes := image.Pixels.Planes
+		seriesPositions, unit, err := planePositions(planes)
+		if err != nil {
+			return nil, err
+		}
+		for _, pos := range seriesPositions {
+			plane := planes[pos.plane]
+			positions = append(positions, StagePosition{
+				Series: seriesIdx,
+				TheT:   plane.TheT,
+				TheZ:   plane.TheZ,
+				TheC:   plane.TheC,
+				X:      pos.x,
+				Y:      pos.y,
+				Z:      pos.z,
+				HasZ:   pos.hasZ,
+				Unit:   unit,
+			})
+		}
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		a, b := positions[i], positions[j]
+		if a.Series != b.Series {
+			return a.Series < b.Series
+		}
+		if a.TheT != b.TheT {
+			return a.TheT < b.TheT
+		}
+		if a.TheZ != b.TheZ {
+			return a.TheZ < b.TheZ
+		}
+		return a.TheC < b.TheC
+	})
+
+	return positions, nil
+}
+
+// GetStagePositionBoundingBox returns the extent of all plane stage positions
+// in a series. Positions are reported in the unit of the first positioned
+// plane. The Z range covers only planes with a PositionZ and is 0 when none
+// has one.
+func GetStagePositionBoundingBox(ome *OME, seriesIdx int) (minX, maxX, minY, maxY, minZ, maxZ float64, unit string, err error) {
+	positions, unit, err := stagePositions(ome, seriesIdx)
+	if err != nil {
+		return 0, 0, 0, 0, 0, 0, "", err
+	}
+
+	var xs, ys, zs []float64
+	for _, pos := range positions {
+		xs = append(xs, pos.x)
+		ys = append(ys, pos.y)
+		if pos.hasZ {
+			zs = append(zs, pos.z)
+		}
+	}
+
+	minX, maxX = floatRange(xs)
+	minY, maxY = floatRange(ys)
+	if len(zs) > 0 {
+		minZ, maxZ = floatRange(zs)
+	}
+
+	return minX, maxX, minY, maxY, minZ, maxZ, unit, nil
+}
+
+// GetStagePositionGrid infers a regular grid layout from the XY stage
+// positions of a series. It returns an error if the positions do not form a
+// fully populated regular grid.
+func GetStagePositionGrid(ome *OME, seriesIdx int) (GridLayout, error) {
+	positions, unit, err := stagePositions(ome, seriesIdx)
+	if err != nil {
+		return GridLayout{}, err
+	}
+
+	xs := make([]float64, len(positions))
+	ys := make([]float64, len(positions))
+	for i, pos := range positions {
+		xs[i], ys[i] = pos.x, pos.y
+	}
+
+	minX, maxX := floatRange(xs)
+	minY, maxY := floatRange(ys)
+	tolerance := math.Max(math.Max(maxX-minX, maxY-minY)*1e-3, 1e-9)
+
+	columns := distinctValues(xs, tolerance)
+	rows := distinctValues(ys, tolerance)
+
+	stepX, ok := regularStep(columns, tolerance)
+	if !ok {
+		return GridLayout{}, fmt.Errorf("stage X positions are not regularly spaced")
+	}
+	stepY, ok := regularStep(rows, tolerance)
+	if !ok {
+		return GridLayout{}, fmt.Errorf("stage Y positions are not regularly spaced")
+	}
+
+	occupied := make(map[[2]int]bool)
+	for i := range xs {
+		col := int(math.Round((xs[i] - minX) / nonZero(stepX)))
+		row := int(math.Round((ys[i] - minY) / nonZero(stepY)))
+		occupied[[2]int{row, col}] = true
+	}
+	if len(occupied) != len(rows)*len(columns) {
+		return GridLayout{}, fmt.Errorf("stage positions do not fill a %dx%d grid", len(rows), len(columns))
+	}
+
+	return GridLayout{
+		Rows:    len(rows),
+		Columns: len(columns),
+		OriginX: minX,
+		OriginY: minY,
+		StepX:   stepX,
+		StepY:   stepY,
+		Unit:    unit,
+	}, nil
+}
+
+// GetStageMoveTime estimates the time a tile scan spent moving the stage.
+// Positioned planes are ordered by DeltaT; whenever the XY position changes
+// between consecutive planes, the interval between them minus the exposure
+// time of the earlier plane is counted as one move. perMove lists the moves
+// in acquisition order and total is their sum.
+func GetStageMoveTime(ome *OME, seriesIdx int) (total time.Duration, perMove []time.Duration, err error) {
+	positions, _, err := stagePositions(ome, seriesIdx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	planes := ome.Images[seriesIdx].Pixels.Planes
+	deltaTs := make([]time.Duration, len(positions))
+	for i, pos := range positions {
+		plane := planes[pos.plane]
+		if deltaTs[i], err = durationFromUnit(plane.DeltaT, plane.DeltaTUnit); err != nil {
+			return 0, nil, err
+		}
+	}
+	order := make([]int, len(positions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return deltaTs[order[i]] < deltaTs[order[j]] })
+
+	for k := 1; k < len(order); k++ {
+		prev, next := order[k-1], order[k]
+		if positions[prev].x == positions[next].x && positions[prev].y == positions[next].y {
+			continue
+		}
+
+		earlier := planes[positions[prev].plane]
+		exposure, err := durationFromUnit(earlier.ExposureTime, earlier.ExposureTimeUnit)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		move := max(deltaTs[next]-deltaTs[prev]-exposure, 0)
+		perMove = append(perMove, move)
+		total += move
+	}
+
+	return total, perMove, nil
+}
+
+// stagePositions returns the plane positions of a series. It returns an
+// error when no plane records a position.
+func stagePositions(ome *OME, seriesIdx int) ([]planePosition, string, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	positions, unit, err := planePositions(image.Pixels.Planes)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(positions) == 0 {
+		return nil, "", fmt.Errorf("series %d has no plane positions", seriesIdx)
+	}
+
+	return positions, unit, nil
+}
+
+// planePositions collects the positions of the planes that record both
+// PositionX and PositionY, converted to the unit of the first of them.
+func planePositions(planes []Plane) (positions []planePosition, unit string, err error) {
+	for i, plane := range planes {
+		if plane.PositionX == nil || plane.PositionY == nil {
+			continue
+		}
+		if len(positions) == 0 {
+			unit = plane.PositionXUnit
+		}
+
+		pos := planePosition{plane: i}
+		if pos.x, err = convertLengthIfSet(*plane.PositionX, plane.PositionXUnit, unit); err != nil {
+			return nil, "", err
+		}
+		if pos.y, err = convertLengthIfSet(*plane.PositionY, plane.PositionYUnit, unit); err != nil {
+			return nil, "", err
+		}
+		if plane.PositionZ != nil {
+			if pos.z, err = convertLengthIfSet(*plane.PositionZ, plane.PositionZUnit, unit); err != nil {
+				return nil, "", err
+			}
+			pos.hasZ = true
+		}
+
+		positions = append(positions, pos)
+	}
+
+	return positions, unit, nil
+}
+
+// convertLengthIfSet converts a length, leaving it untouched when the
+// units already agree or either one is unset.
+func convertLengthIfSet(value float64, from, to string) (float64, error) {
+	if from == to || from == "" || to == "" {
+		return value, nil
+	}
+
+	return convertLength(value, from, to)
+}
+
+// floatRange returns the minimum and maximum of a non-empty slice.
+func floatRange(values []float64) (float64, float64) {
+	lo, hi := values[0], values[0]
+	for _, v := range values[1:] {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	return lo, hi
+}
+
+// distinctValues returns the sorted values that differ by more than tolerance.
+func distinctValues(values []float64, tolerance float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var distinct []float64
+	for _, v := range sorted {
+		if len(distinct) == 0 || v-distinct[len(distinct)-1] > tolerance {
+			distinct = append(distinct, v)
+		}
+	}
+
+	return distinct
+}
+
+// regularStep returns the common spacing of sorted values, if there is one.
+func regularStep(values []float64, tolerance float64) (float64, bool) {
+	if len(values) < 2 {
+		return 0, true
+	}
+
+	step := values[1] - values[0]
+	for i := 2; i < len(values); i++ {
+		if math.Abs(values[i]-values[i-1]-step) > tolerance {
+			return 0, false
+		}
+	}
+
+	return step, true
+}
+
+// nonZero guards grid index calculations for single-row or single-column grids.
+func nonZero(v float64) float64 {
+	if v == 0 {
+		return 1
+	}
+
+	return v
+}
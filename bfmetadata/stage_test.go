@@ -0,0 +1,67 @@
+package bfmetadata
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const stageXML = `<OME xmlns="http://www.openmicroscopy.org/Schemas/OME/2016-06">
+  <Image ID="Image:0">
+    <Pixels ID="Pixels:0" DimensionOrder="XYZCT" Type="uint8" SizeX="1" SizeY="1" SizeZ="1" SizeC="1" SizeT="4">
+      <Plane TheZ="0" TheC="0" TheT="0" DeltaT="0" PositionX="0" PositionY="0" PositionXUnit="µm" PositionYUnit="µm"/>
+      <Plane TheZ="0" TheC="0" TheT="1" DeltaT="1" PositionX="100" PositionY="0" PositionZ="5" PositionXUnit="µm" PositionYUnit="µm" PositionZUnit="µm"/>
+      <Plane TheZ="0" TheC="0" TheT="2" DeltaT="2"/>
+      <Plane TheZ="0" TheC="0" TheT="3" DeltaT="3" PositionX="0.2" PositionY="0.1" PositionXUnit="mm" PositionYUnit="mm"/>
+    </Pixels>
+  </Image>
+  <Image ID="Image:1">
+    <Pixels ID="Pixels:1" DimensionOrder="XYZCT" Type="uint8" SizeX="1" SizeY="1" SizeZ="1" SizeC="1" SizeT="1">
+      <Plane TheZ="0" TheC="0" TheT="0"/>
+    </Pixels>
+  </Image>
+</OME>`
+
+func TestStagePositionsSkipUnpositionedPlanes(t *testing.T) {
+	ome, err := parseXML(stageXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := ome.Images[0].Pixels.Planes[0].PositionX; p == nil || *p != 0 {
+		t.Fatalf("PositionX=\"0\" parsed as %v", p)
+	}
+
+	minX, maxX, minY, maxY, minZ, maxZ, unit, err := GetStagePositionBoundingBox(ome, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if minX != 0 || maxX != 200 || minY != 0 || maxY != 100 || minZ != 5 || maxZ != 5 || unit != "µm" {
+		t.Errorf("bounding box = %v..%v, %v..%v, %v..%v %s", minX, maxX, minY, maxY, minZ, maxZ, unit)
+	}
+
+	_, perMove, err := GetStageMoveTime(ome, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(perMove) != 2 || perMove[1] != 2*time.Second {
+		t.Errorf("moves = %v, want two with the second spanning the unpositioned plane", perMove)
+	}
+}
+
+func TestStagePositionsWithoutAnyPosition(t *testing.T) {
+	ome, err := parseXML(stageXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, _, _, _, _, _, err = GetStagePositionBoundingBox(ome, 1)
+	if err == nil || !strings.Contains(err.Error(), "no plane positions") {
+		t.Errorf("bounding box error = %v", err)
+	}
+	if _, err := GetStagePositionGrid(ome, 1); err == nil {
+		t.Error("GetStagePositionGrid succeeded without positions")
+	}
+	if _, _, err := GetStageMoveTime(ome, 1); err == nil {
+		t.Error("GetStageMoveTime succeeded without positions")
+	}
+}
@@ -0,0 +1,90 @@
+package bfmetadata
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// OMEStats summarises all images of an OME document. PhysicalSizeRange is
+// the smallest and largest X or Y pixel size in µm and AcquisitionDateRange
+// the earliest and latest AcquisitionDate; both are zero when no image sets
+// them. Unique values are sorted.
+type OMEStats struct {
+	TotalImages           int
+	TotalChannels         int
+	TotalPlanes           int
+	UniquePixelTypes      []string
+	UniqueDimensionOrders []string
+	PhysicalSizeRange     [2]float64
+	AcquisitionDateRange  [2]time.Time
+}
+
+// GetOMEXMLStats returns aggregate statistics over all images of ome. A
+// series without Channel elements counts SizeC channels, and each series
+// contributes SizeZ * SizeT planes per channel.
+func GetOMEXMLStats(ome *OME) OMEStats {
+	var stats OMEStats
+	if ome == nil {
+		return stats
+	}
+
+	pixelTypes := make(map[string]bool)
+	dimensionOrders := make(map[string]bool)
+	minSize, maxSize := math.Inf(1), math.Inf(-1)
+
+	for _, image := range ome.Images {
+		p := image.Pixels
+		channels := len(p.Channels)
+		if channels == 0 {
+			channels = p.SizeC
+		}
+
+		stats.TotalImages++
+		stats.TotalChannels += channels
+		stats.TotalPlanes += channels * p.SizeZ * p.SizeT
+
+		if p.Type != "" {
+			pixelTypes[string(p.Type)] = true
+		}
+		if p.DimensionOrder != "" {
+			dimensionOrders[p.DimensionOrder] = true
+		}
+
+		for _, size := range []float64{NormaliseToMicrons(p.PhysicalSizeX, p.PhysicalSizeXUnit), NormaliseToMicrons(p.PhysicalSizeY, p.PhysicalSizeYUnit)} {
+			if size > 0 {
+				minSize = math.Min(minSize, size)
+				maxSize = math.Max(maxSize, size)
+			}
+		}
+
+		if t := image.AcquisitionDate; !t.IsZero() {
+			first, last := &stats.AcquisitionDateRange[0], &stats.AcquisitionDateRange[1]
+			if first.IsZero() || t.Before(*first) {
+				*first = t
+			}
+			if last.IsZero() || t.After(*last) {
+				*last = t
+			}
+		}
+	}
+
+	if minSize <= maxSize {
+		stats.PhysicalSizeRange = [2]float64{minSize, maxSize}
+	}
+	stats.UniquePixelTypes = sortedKeys(pixelTypes)
+	stats.UniqueDimensionOrders = sortedKeys(dimensionOrders)
+
+	return stats
+}
+
+// sortedKeys returns the keys of set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
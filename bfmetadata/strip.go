@@ -0,0 +1,54 @@
+package bfmetadata
+
+import "fmt"
+
+// omeNamespace2016 is the namespace URI of the OME-XML 2016-06 schema.
+const omeNamespace2016 = "http://www.openmicroscopy.org/Schemas/OME/2016-06"
+
+// StripProprietaryData removes every element and namespace-qualified
+// attribute that does not belong to the OME-XML 2016-06 namespace, keeping
+// xsi attributes such as schemaLocation. The document is re-serialised with
+// OME as the default namespace.
+func StripProprietaryData(xmlData string) (string, error) {
+	root, err := parseXMLTree(xmlData)
+	if err != nil {
+		return "", err
+	}
+	if root.Name.Space != omeNamespace2016 {
+		return "", fmt.Errorf("root element is not in the OME 2016-06 namespace (found %q)", root.Name.Space)
+	}
+
+	stripNode(root)
+
+	prefixes := map[string]string{omeNamespace2016: ""}
+	for _, uri := range collectNamespaces(root, make(map[string]bool), nil) {
+		if uri == xsiNamespace {
+			prefixes[uri] = "xsi"
+		}
+	}
+
+	return serializeXMLTree(root, prefixes), nil
+}
+
+// stripNode drops non-OME children and attributes from node recursively.
+func stripNode(node *xmlNode) {
+	attrs := node.Attrs[:0]
+	for _, attr := range node.Attrs {
+		switch attr.Name.Space {
+		case "", omeNamespace2016, xsiNamespace:
+			if !isNamespaceDecl(attr) {
+				attrs = append(attrs, attr)
+			}
+		}
+	}
+	node.Attrs = attrs
+
+	children := node.Children[:0]
+	for _, child := range node.Children {
+		if child.Name.Space == omeNamespace2016 {
+			stripNode(child)
+			children = append(children, child)
+		}
+	}
+	node.Children = children
+}
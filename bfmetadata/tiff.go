@@ -0,0 +1,218 @@
+package bfmetadata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// tiffTagImageDescription is the TIFF tag holding OME-XML in OME-TIFF files.
+const tiffTagImageDescription = 270
+
+// GetOMETIFFCompanionXMLPath reads the ImageDescription of an OME-TIFF and
+// reports whether it refers to an external companion OME-XML file through a
+// BinaryOnly element. The returned path is resolved relative to the TIFF's
+// directory. It returns false when the metadata is stored inline.
+func GetOMETIFFCompanionXMLPath(filePath string) (string, bool, error) {
+	description, err := readTIFFImageDescription(filePath)
+	if err != nil {
+		return "", false, err
+	}
+
+	root, err := parseXMLTree(description)
+	if err != nil {
+		return "", false, fmt.Errorf("error parsing ImageDescription of %s: %w", filePath, err)
+	}
+	if root.Name.Local != "OME" {
+		return "", false, fmt.Errorf("ImageDescription of %s is not OME-XML", filePath)
+	}
+
+	metadataFile := ""
+	for _, attr := range root.Attrs {
+		if attr.Name.Local == "BinaryOnly" {
+			metadataFile = attr.Value
+		}
+	}
+	for _, child := range root.Children {
+		if child.Name.Local != "BinaryOnly" {
+			continue
+		}
+		for _, attr := range child.Attrs {
+			if attr.Name.Local == "MetadataFile" {
+				metadataFile = attr.Value
+			}
+		}
+	}
+
+	if metadataFile == "" {
+		return "", false, nil
+	}
+	if !filepath.IsAbs(metadataFile) {
+		metadataFile = filepath.Join(filepath.Dir(filePath), metadataFile)
+	}
+
+	return metadataFile, true, nil
+}
+
+// readTIFFImageDescription returns the ImageDescription tag of the first IFD
+// of a classic TIFF or BigTIFF file.
+func readTIFFImageDescription(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", &FileNotFoundError{Path: filePath, Err: err}
+	}
+	if err != nil {
+		return "", fmt.Errorf("error opening TIFF file %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("error reading TIFF file %s: %w", filePath, err)
+	}
+	fileSize := uint64(info.Size())
+
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(f, header[:8]); err != nil {
+		return "", fmt.Errorf("error reading TIFF header of %s: %w", filePath, err)
+	}
+
+	var order binary.ByteOrder
+	switch string(header[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return "", fmt.Errorf("%s is not a TIFF file", filePath)
+	}
+
+	bigTIFF := false
+	var ifdOffset uint64
+	switch order.Uint16(header[2:4]) {
+	case 42:
+		ifdOffset = uint64(order.Uint32(header[4:8]))
+	case 43:
+		bigTIFF = true
+		if _, err := io.ReadFull(f, header[8:16]); err != nil {
+			return "", fmt.Errorf("error reading BigTIFF header of %s: %w", filePath, err)
+		}
+		ifdOffset = order.Uint64(header[8:16])
+	default:
+		return "", fmt.Errorf("%s is not a TIFF file", filePath)
+	}
+
+	countSize, entrySize, valueSize := 2, 12, 4
+	if bigTIFF {
+		countSize, entrySize, valueSize = 8, 20, 8
+	}
+
+	if ifdOffset > fileSize {
+		return "", fmt.Errorf("IFD offset %d of %s is beyond the end of the file", ifdOffset, filePath)
+	}
+
+	countBuf := make([]byte, countSize)
+	if _, err := f.ReadAt(countBuf, int64(ifdOffset)); err != nil {
+		return "", fmt.Errorf("error reading IFD of %s: %w", filePath, err)
+	}
+	var entryCount uint64
+	if bigTIFF {
+		entryCount = order.Uint64(countBuf)
+	} else {
+		entryCount = uint64(order.Uint16(countBuf))
+	}
+
+	// Checking the count against the file size before multiplying keeps the
+	// allocation bounded and the byte size from overflowing.
+	if entryCount > (fileSize-ifdOffset)/uint64(entrySize) {
+		return "", fmt.Errorf("IFD of %s declares %d entries, more than the file holds", filePath, entryCount)
+	}
+
+	entries := make([]byte, entryCount*uint64(entrySize))
+	if _, err := f.ReadAt(entries, int64(ifdOffset)+int64(countSize)); err != nil {
+		return "", fmt.Errorf("error reading IFD entries of %s: %w", filePath, err)
+	}
+
+	for i := uint64(0); i < entryCount; i++ {
+		entry := entries[i*uint64(entrySize) : (i+1)*uint64(entrySize)]
+		if order.Uint16(entry[0:2]) != tiffTagImageDescription {
+			continue
+		}
+
+		var count uint64
+		value := entry[4+valueSize:]
+		if bigTIFF {
+			count = order.Uint64(entry[4:12])
+		} else {
+			count = uint64(order.Uint32(entry[4:8]))
+			value = entry[8:12]
+		}
+
+		if count > fileSize {
+			return "", fmt.Errorf("ImageDescription of %s declares %d bytes, more than the file holds", filePath, count)
+		}
+
+		data := make([]byte, count)
+		if count <= uint64(valueSize) {
+			copy(data, value)
+		} else {
+			var offset uint64
+			if bigTIFF {
+				offset = order.Uint64(value)
+			} else {
+				offset = uint64(order.Uint32(value))
+			}
+			if offset > fileSize-count {
+				return "", fmt.Errorf("ImageDescription of %s extends beyond the end of the file", filePath)
+			}
+			if _, err := f.ReadAt(data, int64(offset)); err != nil {
+				return "", fmt.Errorf("error reading ImageDescription of %s: %w", filePath, err)
+			}
+		}
+
+		return string(bytes.TrimRight(data, "\x00")), nil
+	}
+
+	return "", fmt.Errorf("no ImageDescription tag found in %s", filePath)
+}
+
+// EncodeOMEXMLForTIFF prepares OME-XML for storage in a TIFF ImageDescription
+// tag. Invalid UTF-8 sequences are replaced, embedded NUL characters are
+// removed and the terminating NUL required by TIFF ASCII fields is appended.
+func EncodeOMEXMLForTIFF(xmlData string) string {
+	xmlData = strings.ToValidUTF8(xmlData, "�")
+	xmlData = strings.ReplaceAll(xmlData, "\x00", "")
+
+	return xmlData + "\x00"
+}
+
+// DecodeOMEXMLFromTIFF recovers OME-XML from an ImageDescription tag value,
+// stripping the TIFF NUL terminator. Base64-encoded values written by some
+// tools are decoded as well.
+func DecodeOMEXMLFromTIFF(tagValue string) (string, error) {
+	xmlData := strings.TrimSpace(strings.TrimRight(tagValue, "\x00"))
+
+	if !strings.HasPrefix(xmlData, "<") {
+		decoded, err := base64.StdEncoding.DecodeString(xmlData)
+		if err != nil {
+			return "", fmt.Errorf("ImageDescription is neither XML nor base64: %w", err)
+		}
+		xmlData = strings.TrimSpace(string(decoded))
+	}
+
+	if !utf8.ValidString(xmlData) {
+		return "", fmt.Errorf("ImageDescription is not valid UTF-8")
+	}
+	if !strings.Contains(xmlData, "<OME") && !strings.Contains(xmlData, ":OME") {
+		return "", fmt.Errorf("no OME-XML content found in ImageDescription")
+	}
+
+	return xmlData, nil
+}
@@ -0,0 +1,81 @@
+package bfmetadata
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// classicTIFF returns a little-endian TIFF whose only IFD holds an
+// ImageDescription of count bytes stored after the IFD.
+func classicTIFF(description string, count uint32) []byte {
+	buf := []byte("II")
+	buf = binary.LittleEndian.AppendUint16(buf, 42)
+	buf = binary.LittleEndian.AppendUint32(buf, 8)
+
+	buf = binary.LittleEndian.AppendUint16(buf, 1)
+	buf = binary.LittleEndian.AppendUint16(buf, tiffTagImageDescription)
+	buf = binary.LittleEndian.AppendUint16(buf, 2)
+	buf = binary.LittleEndian.AppendUint32(buf, count)
+	buf = binary.LittleEndian.AppendUint32(buf, 8+2+12+4)
+	buf = binary.LittleEndian.AppendUint32(buf, 0)
+
+	return append(buf, description...)
+}
+
+func writeTIFF(t *testing.T, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "image.ome.tif")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestGetOMETIFFCompanionXMLPath(t *testing.T) {
+	description := `<OME xmlns="http://www.openmicroscopy.org/Schemas/OME/2016-06"><BinaryOnly MetadataFile="image.companion.ome" UUID="urn:uuid:0"/></OME>` + "\x00"
+	path := writeTIFF(t, classicTIFF(description, uint32(len(description))))
+
+	companion, ok, err := GetOMETIFFCompanionXMLPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || companion != filepath.Join(filepath.Dir(path), "image.companion.ome") {
+		t.Errorf("got %q, %v", companion, ok)
+	}
+}
+
+func TestReadTIFFImageDescriptionRejectsOversizedCounts(t *testing.T) {
+	bigTIFF := []byte("II")
+	bigTIFF = binary.LittleEndian.AppendUint16(bigTIFF, 43)
+	bigTIFF = binary.LittleEndian.AppendUint16(bigTIFF, 8)
+	bigTIFF = binary.LittleEndian.AppendUint16(bigTIFF, 0)
+	bigTIFF = binary.LittleEndian.AppendUint64(bigTIFF, 16)
+	bigTIFF = binary.LittleEndian.AppendUint64(bigTIFF, 1<<62)
+	bigTIFF = append(bigTIFF, make([]byte, 8)...)
+
+	beyondEnd := classicTIFF("", 0)
+	binary.LittleEndian.PutUint32(beyondEnd[4:8], 1<<30)
+
+	farValue := classicTIFF("<OME/>", 6)
+	binary.LittleEndian.PutUint32(farValue[18:22], 1<<30)
+
+	tests := map[string][]byte{
+		"entry count":        bigTIFF,
+		"IFD offset":         beyondEnd,
+		"description length": classicTIFF("<OME/>", 1<<31),
+		"description offset": farValue,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := GetOMETIFFCompanionXMLPath(writeTIFF(t, data))
+			if err == nil || !strings.Contains(err.Error(), "file") {
+				t.Errorf("got error %v, want a file size error", err)
+			}
+		})
+	}
+}
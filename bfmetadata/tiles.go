@@ -0,0 +1,25 @@
+package bfmetadata
+
+// GetTileCoordinates returns the tile containing pixel (x, y) in a grid of
+// tileWidth by tileHeight tiles starting at the image origin, and the
+// pixel's offset within that tile. All results are zero when the tile size
+// is not positive.
+func GetTileCoordinates(x, y, tileWidth, tileHeight int) (tileRow, tileCol, pixelXInTile, pixelYInTile int) {
+	if tileWidth <= 0 || tileHeight <= 0 {
+		return 0, 0, 0, 0
+	}
+
+	return y / tileHeight, x / tileWidth, x % tileWidth, y % tileHeight
+}
+
+// GetTilePixelRegion returns the pixel region of an image covered by a
+// tile. Tiles on the right and bottom edges are clipped to the image size; a
+// tile outside the image yields a zero width or height.
+func GetTilePixelRegion(tileRow, tileCol, tileWidth, tileHeight, imageSizeX, imageSizeY int) (x, y, width, height int) {
+	x = tileCol * tileWidth
+	y = tileRow * tileHeight
+	width = max(min(tileWidth, imageSizeX-x), 0)
+	height = max(min(tileHeight, imageSizeY-y), 0)
+
+	return x, y, width, height
+}
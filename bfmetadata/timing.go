@@ -0,0 +1,215 @@
+package bfmetadata
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// acquisitionDateLayouts lists the ISO 8601 variants found in OME-XML files.
+var acquisitionDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// mtimeSkewTolerance is how far a file's modification time may predate its
+// acquisition date before the modification time is considered unreliable.
+const mtimeSkewTolerance = 24 * time.Hour
+
+// timeUnits maps OME time unit symbols to durations.
+var timeUnits = map[string]time.Duration{
+	"h":   time.Hour,
+	"min": time.Minute,
+	"s":   time.Second,
+	"ms":  time.Millisecond,
+	"µs":  time.Microsecond,
+	"μs":  time.Microsecond,
+	"us":  time.Microsecond,
+	"ns":  time.Nanosecond,
+}
+
+// GetTimeRange returns the acquisition start, the time of the last plane and
+// the duration between them. The start is the image AcquisitionDate and the
+// duration is the largest plane DeltaT. When AcquisitionDate is missing both
+// start and end are zero but the duration is still reported.
+func GetTimeRange(ome *OME, seriesIdx int) (start, end time.Time, duration time.Duration, err error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, err
+	}
+
+	for _, plane := range image.Pixels.Planes {
+		deltaT, err := durationFromUnit(plane.DeltaT, plane.DeltaTUnit)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, err
+		}
+		if deltaT > duration {
+			duration = deltaT
+		}
+	}
+
+	if image.AcquisitionDateRaw == "" {
+		return time.Time{}, time.Time{}, duration, nil
+	}
+
+	start, err = acquisitionTime(image)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, err
+	}
+
+	return start, start.Add(duration), duration, nil
+}
+
+// GetSeriesCreationTime returns the AcquisitionDate of a series as a time.Time.
+func GetSeriesCreationTime(ome *OME, seriesIdx int) (time.Time, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if image.AcquisitionDateRaw == "" {
+		return time.Time{}, fmt.Errorf("series %d has no acquisition date", seriesIdx)
+	}
+
+	return acquisitionTime(image)
+}
+
+// GetSeriesCreationTimes returns the acquisition time of every series,
+// indexed by series.
+func GetSeriesCreationTimes(ome *OME) ([]time.Time, error) {
+	if ome == nil {
+		return nil, fmt.Errorf("no OME metadata provided")
+	}
+
+	times := make([]time.Time, len(ome.Images))
+	for i := range ome.Images {
+		t, err := GetSeriesCreationTime(ome, i)
+		if err != nil {
+			return nil, err
+		}
+		times[i] = t
+	}
+
+	return times, nil
+}
+
+// GetAbsoluteTimestampForPlane returns the absolute time at which a plane
+// was acquired, computed as the series AcquisitionDate plus the plane DeltaT.
+func GetAbsoluteTimestampForPlane(ome *OME, seriesIdx, z, c, t int) (time.Time, error) {
+	image, err := getImage(ome, seriesIdx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if image.AcquisitionDateRaw == "" {
+		return time.Time{}, fmt.Errorf("series %d has no acquisition date", seriesIdx)
+	}
+
+	plane, err := findPlane(image, z, c, t)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	start, err := acquisitionTime(image)
+	if err != nil {
+		return time.Time{}, err
+	}
+	deltaT, err := durationFromUnit(plane.DeltaT, plane.DeltaTUnit)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return start.Add(deltaT), nil
+}
+
+// GetMetadataLastModified is a wrapper around the default Client's GetMetadataLastModified.
+func GetMetadataLastModified(ctx context.Context, filePath string) (time.Time, error) {
+	return defaultClient.GetMetadataLastModified(ctx, filePath)
+}
+
+// GetMetadataLastModified returns the later of the file modification time
+// and the latest AcquisitionDate of any series. Metadata edited after
+// acquisition, e.g. by OMERO, is reflected in the modification time. A
+// warning is logged when the modification time predates the acquisition by
+// more than a day, which usually means the mtime was not preserved on copy.
+func (c *Client) GetMetadataLastModified(ctx context.Context, filePath string) (time.Time, error) {
+	if err := checkInputFile(filePath); err != nil {
+		return time.Time{}, err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error reading modification time of %s: %w", filePath, err)
+	}
+	modified := info.ModTime()
+
+	metadata, err := c.getParsedMetadata(ctx, filePath)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var acquired time.Time
+	for _, image := range metadata.Images {
+		if image.AcquisitionDateRaw == "" {
+			continue
+		}
+		t, err := acquisitionTime(&image)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if t.After(acquired) {
+			acquired = t
+		}
+	}
+
+	if acquired.IsZero() || !acquired.After(modified) {
+		return modified, nil
+	}
+	if acquired.Sub(modified) > mtimeSkewTolerance {
+		c.logf(LogLevelWarn, "modification time %s of %s predates its acquisition date %s", modified.Format(time.RFC3339), filePath, acquired.Format(time.RFC3339))
+	}
+
+	return acquired, nil
+}
+
+// acquisitionTime returns the parsed AcquisitionDate of an image that has
+// one, reporting an error when its raw value could not be parsed.
+func acquisitionTime(image *Image) (time.Time, error) {
+	if image.AcquisitionDate.IsZero() {
+		return time.Time{}, fmt.Errorf("error parsing acquisition date %q", image.AcquisitionDateRaw)
+	}
+
+	return image.AcquisitionDate, nil
+}
+
+// parseAcquisitionDate parses an OME AcquisitionDate value.
+func parseAcquisitionDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range acquisitionDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("error parsing acquisition date %q", value)
+}
+
+// durationFromUnit converts an OME time value to a time.Duration.
+// An empty unit is treated as seconds, which is the OME default.
+func durationFromUnit(value float64, unit string) (time.Duration, error) {
+	unit = strings.TrimSpace(unit)
+	if unit == "" {
+		unit = "s"
+	}
+
+	scale, ok := timeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unsupported time unit %q", unit)
+	}
+
+	return time.Duration(value * float64(scale)), nil
+}
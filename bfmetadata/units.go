@@ -0,0 +1,74 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// lengthUnitsInMicrons maps OME length unit symbols to their size in micrometers.
+var lengthUnitsInMicrons = map[string]float64{
+	"m":  1e6,
+	"cm": 1e4,
+	"mm": 1e3,
+	"µm": 1,
+	"μm": 1,
+	"um": 1,
+	"nm": 1e-3,
+	"pm": 1e-6,
+	"Å":  1e-4,
+	"in": 25400,
+	"ft": 304800,
+}
+
+// convertLength converts a length value between two OME length units.
+// An empty unit is treated as micrometers, which is the OME default.
+func convertLength(value float64, from, to string) (float64, error) {
+	fromFactor, err := lengthFactor(from)
+	if err != nil {
+		return 0, err
+	}
+	toFactor, err := lengthFactor(to)
+	if err != nil {
+		return 0, err
+	}
+
+	return value * fromFactor / toFactor, nil
+}
+
+// NormaliseToMicrons converts a length in an OME length unit such as "nm",
+// "µm" or "mm" to micrometers. An empty unit is treated as micrometers; an
+// unrecognised unit yields NaN.
+func NormaliseToMicrons(size float64, unit string) float64 {
+	microns, err := convertLength(size, unit, "µm")
+	if err != nil {
+		return math.NaN()
+	}
+
+	return microns
+}
+
+// lengthFactor returns the size of the given unit in micrometers.
+func lengthFactor(unit string) (float64, error) {
+	unit = strings.TrimSpace(unit)
+	if unit == "" {
+		return 1, nil
+	}
+
+	factor, ok := lengthUnitsInMicrons[unit]
+	if !ok {
+		return 0, fmt.Errorf("unsupported length unit %q", unit)
+	}
+
+	return factor, nil
+}
+
+// wavelengthNm converts a wavelength to nanometers. An empty unit is treated
+// as nanometers, which is the OME default for wavelengths.
+func wavelengthNm(value float64, unit string) (float64, error) {
+	if strings.TrimSpace(unit) == "" {
+		return value, nil
+	}
+
+	return convertLength(value, unit, "nm")
+}
@@ -0,0 +1,127 @@
+package bfmetadata
+
+import (
+	"context"
+	"fmt"
+)
+
+// ValidationWarning describes a non-fatal problem found in OME metadata.
+type ValidationWarning struct {
+	Path    string
+	Message string
+}
+
+func (w ValidationWarning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// validDimensionOrders lists the DimensionOrder values allowed by the OME schema.
+var validDimensionOrders = map[string]bool{
+	"XYZCT": true, "XYZTC": true, "XYCTZ": true,
+	"XYCZT": true, "XYTCZ": true, "XYTZC": true,
+}
+
+// ValidateOMEXML checks parsed OME metadata for missing or inconsistent
+// values and returns one warning per problem found.
+func ValidateOMEXML(ome *OME) []ValidationWarning {
+	var warnings []ValidationWarning
+	warn := func(path, format string, args ...interface{}) {
+		warnings = append(warnings, ValidationWarning{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if ome == nil || len(ome.Images) == 0 {
+		warn("OME", "no Image elements found")
+		return warnings
+	}
+
+	for i, image := range ome.Images {
+		path := fmt.Sprintf("Image[%d]", i)
+		p := image.Pixels
+
+		if image.ID == "" {
+			warn(path, "missing ID")
+		}
+		if p.ID == "" {
+			warn(path+"/Pixels", "missing ID")
+		}
+		if !validDimensionOrders[p.DimensionOrder] {
+			warn(path+"/Pixels", "invalid DimensionOrder %q", p.DimensionOrder)
+		}
+		if p.Type == "" {
+			warn(path+"/Pixels", "missing Type")
+		}
+
+		sizes := []struct {
+			name  string
+			value int
+		}{{"SizeX", p.SizeX}, {"SizeY", p.SizeY}, {"SizeZ", p.SizeZ}, {"SizeC", p.SizeC}, {"SizeT", p.SizeT}}
+		for _, size := range sizes {
+			if size.value < 1 {
+				warn(path+"/Pixels", "%s must be positive, got %d", size.name, size.value)
+			}
+		}
+
+		physical := []struct {
+			name  string
+			value float64
+			raw   string
+		}{
+			{"PhysicalSizeX", p.PhysicalSizeX, p.PhysicalSizeXRaw},
+			{"PhysicalSizeY", p.PhysicalSizeY, p.PhysicalSizeYRaw},
+			{"PhysicalSizeZ", p.PhysicalSizeZ, p.PhysicalSizeZRaw},
+		}
+		for _, size := range physical {
+			if size.raw != "" && size.value <= 0 {
+				warn(path+"/Pixels", "%s must be a positive number, got %q", size.name, size.raw)
+			}
+		}
+
+		if len(p.Channels) > 0 {
+			samples := 0
+			for _, channel := range p.Channels {
+				samples += samplesPerPixel(channel)
+			}
+			if samples != p.SizeC {
+				warn(path+"/Pixels", "channels provide %d samples but SizeC is %d", samples, p.SizeC)
+			}
+		}
+
+		for j, plane := range p.Planes {
+			if plane.TheZ < 0 || plane.TheZ >= p.SizeZ ||
+				plane.TheC < 0 || plane.TheC >= p.SizeC ||
+				plane.TheT < 0 || plane.TheT >= p.SizeT {
+				warn(fmt.Sprintf("%s/Pixels/Plane[%d]", path, j), "plane index (Z=%d, C=%d, T=%d) outside image dimensions", plane.TheZ, plane.TheC, plane.TheT)
+			}
+		}
+
+		if image.AcquisitionDateRaw != "" {
+			if image.AcquisitionDate.IsZero() {
+				warn(path, "unparseable AcquisitionDate %q", image.AcquisitionDateRaw)
+			}
+		}
+	}
+
+	return warnings
+}
+
+// GetOMEXMLWithValidation is a wrapper around the default Client's GetOMEXMLWithValidation.
+func GetOMEXMLWithValidation(ctx context.Context, filePath string) (string, []ValidationWarning, error) {
+	return defaultClient.GetOMEXMLWithValidation(ctx, filePath)
+}
+
+// GetOMEXMLWithValidation extracts OME-XML from filePath and validates it.
+// Validation problems are returned as warnings; an error is returned only
+// when extraction fails or the XML cannot be parsed at all.
+func (c *Client) GetOMEXMLWithValidation(ctx context.Context, filePath string) (string, []ValidationWarning, error) {
+	xmlData, err := c.GetOmexmlMetadata(ctx, filePath, AllSeries)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ome, err := parseXML(xmlData)
+	if err != nil {
+		return xmlData, nil, fmt.Errorf("error parsing OME-XML: %w", err)
+	}
+
+	return xmlData, ValidateOMEXML(ome), nil
+}
@@ -0,0 +1,83 @@
+package bfmetadata
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+var (
+	bfVersionOnce sync.Once
+	bfVersion     string
+	bfVersionErr  error
+)
+
+// GetBioFormatsVersion returns the Implementation-Version of the embedded
+// bioformats_package.jar, e.g. "8.0.1". It reads the jar manifest directly
+// and does not start a JVM.
+func GetBioFormatsVersion() (string, error) {
+	bfVersionOnce.Do(func() {
+		bfVersion, bfVersionErr = readJarVersion(bioformatsJar)
+	})
+
+	return bfVersion, bfVersionErr
+}
+
+// readJarVersion returns the Implementation-Version entry of a jar's
+// META-INF/MANIFEST.MF.
+func readJarVersion(jar []byte) (string, error) {
+	r, err := zip.NewReader(bytes.NewReader(jar), int64(len(jar)))
+	if err != nil {
+		return "", fmt.Errorf("error opening jar: %w", err)
+	}
+
+	f, err := r.Open("META-INF/MANIFEST.MF")
+	if err != nil {
+		return "", fmt.Errorf("error opening jar manifest: %w", err)
+	}
+	defer f.Close()
+
+	attrs, err := parseManifest(f)
+	if err != nil {
+		return "", err
+	}
+
+	v, ok := attrs["Implementation-Version"]
+	if !ok {
+		return "", fmt.Errorf("no Implementation-Version in jar manifest")
+	}
+
+	return v, nil
+}
+
+// parseManifest reads the main section of a jar manifest. Lines starting
+// with a space continue the previous value.
+func parseManifest(r io.Reader) (map[string]string, error) {
+	attrs := make(map[string]string)
+	last := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, " ") && last != "" {
+			attrs[last] += line[1:]
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			last = strings.TrimSpace(key)
+			attrs[last] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading jar manifest: %w", err)
+	}
+
+	return attrs, nil
+}
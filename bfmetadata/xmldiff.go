@@ -0,0 +1,92 @@
+package bfmetadata
+
+import (
+	"fmt"
+	"sort"
+)
+
+// XMLDiff describes one XML node that differs between two documents. Type is
+// "element", "attribute" or "text"; an empty type means the node is absent
+// from that document.
+type XMLDiff struct {
+	XPath  string
+	TypeA  string
+	ValueA string
+	TypeB  string
+	ValueB string
+}
+
+// xmlEntry is a flattened XML node keyed by its XPath.
+type xmlEntry struct {
+	Type  string
+	Value string
+}
+
+// DiffOMEXML compares two OME-XML documents node by node. The comparison
+// ignores attribute order, insignificant whitespace, comments and namespace
+// prefixes. Differences are returned sorted by XPath.
+func DiffOMEXML(xmlA, xmlB string) ([]XMLDiff, error) {
+	rootA, err := parseXMLTree(xmlA)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing first document: %w", err)
+	}
+	rootB, err := parseXMLTree(xmlB)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing second document: %w", err)
+	}
+
+	entriesA := make(map[string]xmlEntry)
+	entriesB := make(map[string]xmlEntry)
+	flattenXMLNode(rootA, "/"+rootA.Name.Local+"[1]", entriesA)
+	flattenXMLNode(rootB, "/"+rootB.Name.Local+"[1]", entriesB)
+
+	paths := make(map[string]bool)
+	for path := range entriesA {
+		paths[path] = true
+	}
+	for path := range entriesB {
+		paths[path] = true
+	}
+
+	var diffs []XMLDiff
+	for path := range paths {
+		a, b := entriesA[path], entriesB[path]
+		if a == b {
+			continue
+		}
+		diffs = append(diffs, XMLDiff{
+			XPath:  path,
+			TypeA:  a.Type,
+			ValueA: a.Value,
+			TypeB:  b.Type,
+			ValueB: b.Value,
+		})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].XPath < diffs[j].XPath })
+	return diffs, nil
+}
+
+// flattenXMLNode records node, its attributes, text and descendants under
+// XPath keys such as /OME[1]/Image[2]/@Name.
+func flattenXMLNode(node *xmlNode, path string, entries map[string]xmlEntry) {
+	entries[path] = xmlEntry{Type: "element"}
+
+	for _, attr := range node.Attrs {
+		if isNamespaceDecl(attr) {
+			continue
+		}
+		entries[path+"/@"+attr.Name.Local] = xmlEntry{Type: "attribute", Value: attr.Value}
+	}
+
+	if node.Text != "" {
+		entries[path+"/text()"] = xmlEntry{Type: "text", Value: node.Text}
+	}
+
+	counts := make(map[string]int)
+	for _, child := range node.Children {
+		counts[child.Name.Local]++
+		childPath := fmt.Sprintf("%s/%s[%d]", path, child.Name.Local, counts[child.Name.Local])
+		flattenXMLNode(child, childPath, entries)
+	}
+}
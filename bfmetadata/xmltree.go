@@ -0,0 +1,225 @@
+package bfmetadata
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// xmlNode is a generic element tree used for XML-level comparisons and rewrites.
+type xmlNode struct {
+	Name     xml.Name
+	Attrs    []xml.Attr
+	Text     string
+	Children []*xmlNode
+}
+
+// parseXMLTree decodes an XML document into a generic element tree.
+// Character data is whitespace-trimmed; comments and processing
+// instructions are discarded.
+func parseXMLTree(xmlData string) (*xmlNode, error) {
+	decoder := xml.NewDecoder(strings.NewReader(xmlData))
+
+	var root *xmlNode
+	var stack []*xmlNode
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, newXMLParseError([]byte(xmlData), decoder.InputOffset(), err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{Name: t.Name, Attrs: append([]xml.Attr(nil), t.Attr...)}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("multiple root elements in XML document")
+				}
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no root element found in XML document")
+	}
+
+	trimText(root)
+	return root, nil
+}
+
+// trimText strips surrounding whitespace from all character data in the tree.
+func trimText(node *xmlNode) {
+	node.Text = strings.TrimSpace(node.Text)
+	for _, child := range node.Children {
+		trimText(child)
+	}
+}
+
+// isNamespaceDecl reports whether attr is an xmlns declaration.
+func isNamespaceDecl(attr xml.Attr) bool {
+	return attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+}
+
+// canonicalXML serialises a document in a form that is stable across
+// formatting changes: comments and namespace declarations are dropped,
+// attributes are sorted, element names lose their prefixes and runs of
+// whitespace in character data collapse to a single space.
+func canonicalXML(xmlData string) (string, error) {
+	root, err := parseXMLTree(xmlData)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	writeCanonicalNode(&buf, root)
+	return buf.String(), nil
+}
+
+func writeCanonicalNode(buf *bytes.Buffer, node *xmlNode) {
+	attrs := make([]xml.Attr, 0, len(node.Attrs))
+	for _, attr := range node.Attrs {
+		if !isNamespaceDecl(attr) {
+			attrs = append(attrs, attr)
+		}
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].Name.Local != attrs[j].Name.Local {
+			return attrs[i].Name.Local < attrs[j].Name.Local
+		}
+		return attrs[i].Name.Space < attrs[j].Name.Space
+	})
+
+	buf.WriteString("<" + node.Name.Local)
+	for _, attr := range attrs {
+		buf.WriteString(" " + attr.Name.Local + `="`)
+		xml.EscapeText(buf, []byte(attr.Value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+
+	xml.EscapeText(buf, []byte(strings.Join(strings.Fields(node.Text), " ")))
+	for _, child := range node.Children {
+		writeCanonicalNode(buf, child)
+	}
+
+	buf.WriteString("</" + node.Name.Local + ">")
+}
+
+// Namespace URIs with fixed, conventional prefixes.
+const (
+	xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+	xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+)
+
+// serializeXMLTree writes the tree as an XML document, qualifying names with
+// the prefix assigned to each namespace URI in prefixes. An empty prefix
+// makes that namespace the default namespace. All declarations are placed
+// on the root element.
+func serializeXMLTree(root *xmlNode, prefixes map[string]string) string {
+	uris := make([]string, 0, len(prefixes))
+	for uri := range prefixes {
+		uris = append(uris, uri)
+	}
+	sort.Slice(uris, func(i, j int) bool { return prefixes[uris[i]] < prefixes[uris[j]] })
+
+	var decls []xml.Attr
+	for _, uri := range uris {
+		if uri == xmlNamespace {
+			continue
+		}
+		if prefixes[uri] == "" {
+			decls = append(decls, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: uri})
+		} else {
+			decls = append(decls, xml.Attr{Name: xml.Name{Local: "xmlns:" + prefixes[uri]}, Value: uri})
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	writeXMLNode(&buf, root, prefixes, decls)
+	return buf.String()
+}
+
+func writeXMLNode(buf *bytes.Buffer, node *xmlNode, prefixes map[string]string, decls []xml.Attr) {
+	name := qualifiedName(node.Name, prefixes)
+
+	buf.WriteString("<" + name)
+	for _, attr := range decls {
+		writeXMLAttr(buf, attr.Name.Local, attr.Value)
+	}
+	for _, attr := range node.Attrs {
+		if isNamespaceDecl(attr) {
+			continue
+		}
+		writeXMLAttr(buf, qualifiedName(attr.Name, prefixes), attr.Value)
+	}
+
+	if node.Text == "" && len(node.Children) == 0 {
+		buf.WriteString("/>")
+		return
+	}
+
+	buf.WriteString(">")
+	xml.EscapeText(buf, []byte(node.Text))
+	for _, child := range node.Children {
+		writeXMLNode(buf, child, prefixes, nil)
+	}
+	buf.WriteString("</" + name + ">")
+}
+
+func writeXMLAttr(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(" " + name + `="`)
+	xml.EscapeText(buf, []byte(value))
+	buf.WriteString(`"`)
+}
+
+// qualifiedName returns the prefixed form of name. Names in the default
+// namespace, and attributes without a namespace, are left unprefixed.
+func qualifiedName(name xml.Name, prefixes map[string]string) string {
+	if prefix := prefixes[name.Space]; name.Space != "" && prefix != "" {
+		return prefix + ":" + name.Local
+	}
+
+	return name.Local
+}
+
+// collectNamespaces returns the namespace URIs used by element and
+// attribute names in the tree, in document order.
+func collectNamespaces(node *xmlNode, seen map[string]bool, uris []string) []string {
+	add := func(uri string) {
+		if uri != "" && uri != "xmlns" && !seen[uri] {
+			seen[uri] = true
+			uris = append(uris, uri)
+		}
+	}
+
+	add(node.Name.Space)
+	for _, attr := range node.Attrs {
+		if !isNamespaceDecl(attr) {
+			add(attr.Name.Space)
+		}
+	}
+	for _, child := range node.Children {
+		uris = collectNamespaces(child, seen, uris)
+	}
+
+	return uris
+}